@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ageBucketLimits splits files by how long ago they were modified or accessed.
+// A file lands in the first bucket whose Age it is younger than; zero means no limit.
+var ageBucketLimits = []struct {
+	Label string
+	Age   time.Duration
+}{
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"< 1 month", 30 * 24 * time.Hour},
+	{"< 6 months", 180 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+	{"Older", 0},
+}
+
+// coldAgeBucket is the first bucket considered cold data worth archiving.
+const coldAgeBucket = 3
+
+type ageBucket struct {
+	Label    string
+	Modified ageStat
+	Accessed ageStat
+}
+
+type ageStat struct {
+	Size    int64
+	Count   int64
+	Largest []fileEntry
+}
+
+func (s *ageStat) add(file fileEntry) {
+	s.Size += file.Size
+	s.Count++
+	if file.Size > 0 {
+		s.Largest = appendLargest(s.Largest, file)
+	}
+}
+
+func (s *ageStat) merge(src ageStat) {
+	s.Size += src.Size
+	s.Count += src.Count
+	for _, file := range src.Largest {
+		s.Largest = appendLargest(s.Largest, file)
+	}
+}
+
+func ageBucketIndex(t, now time.Time) int {
+	age := now.Sub(t)
+	for i, limit := range ageBucketLimits {
+		if limit.Age == 0 || age < limit.Age {
+			return i
+		}
+	}
+	return len(ageBucketLimits) - 1
+}
+
+// ageStat returns the bucket stat for the selected clock, modified or accessed.
+func (m model) ageStat(bucket ageBucket) ageStat {
+	if m.ageByAccess {
+		return bucket.Accessed
+	}
+	return bucket.Modified
+}
+
+func (m model) currentAgeFiles() ([]fileEntry, bool) {
+	if m.insights == nil || !m.ageDrill || m.ageCursor.Selected >= len(m.insights.Ages) {
+		return nil, false
+	}
+	return m.ageStat(m.insights.Ages[m.ageCursor.Selected]).Largest, true
+}
+
+func (m model) updateAgesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	files, drilled := m.currentAgeFiles()
+	var buckets []ageBucket
+	if m.insights != nil {
+		buckets = m.insights.Ages
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.ageDrill = false
+		return m.refreshCurrentPath()
+	case "esc", "b", "left", "h", "A":
+		if drilled && msg.String() != "A" {
+			m.ageDrill = false
+			return m, nil
+		}
+		m.showAges = false
+		m.ageDrill = false
+		return m, nil
+	case "tab":
+		// Switch between last-modified and last-access ages
+		m.ageByAccess = !m.ageByAccess
+		m.ageFileCursor = listCursor{}
+	case "up", "k":
+		if drilled {
+			m.ageFileCursor.up()
+		} else {
+			m.ageCursor.up()
+		}
+	case "down", "j":
+		if drilled {
+			m.ageFileCursor.down(len(files), headedViewport(m.height))
+		} else {
+			m.ageCursor.down(len(buckets), headedViewport(m.height))
+		}
+	case "enter", "right", "l":
+		if !drilled && m.ageCursor.Selected < len(buckets) {
+			m.ageDrill = true
+			m.ageFileCursor = listCursor{}
+		}
+	case "o", "f", "F":
+		if drilled && m.ageFileCursor.Selected < len(files) {
+			file := files[m.ageFileCursor.Selected]
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(file.Path)
+		}
+	case "delete", "backspace":
+		if drilled && m.ageFileCursor.Selected < len(files) {
+			file := files[m.ageFileCursor.Selected]
+			m.deleteConfirm = true
+			m.deleteTarget = &dirEntry{Name: file.Name, Path: file.Path, Size: file.Size}
+		}
+	}
+	return m, nil
+}
+
+func (m model) ageClockLabel() string {
+	if m.ageByAccess {
+		return "last accessed"
+	}
+	return "last modified"
+}
+
+func (m model) renderAges(b *strings.Builder) {
+	if m.insights == nil || len(m.insights.Ages) == 0 {
+		fmt.Fprintln(b, "  No file age data for this scan, press R to rescan")
+		return
+	}
+	if files, ok := m.currentAgeFiles(); ok {
+		bucket := m.insights.Ages[m.ageCursor.Selected]
+		stat := m.ageStat(bucket)
+		clock := "Modified"
+		if m.ageByAccess {
+			clock = "Accessed"
+		}
+		fmt.Fprintf(b, "%s🕰  %s %s: %s in %s files%s\n\n", colorGray, clock, strings.ToLower(bucket.Label),
+			humanizeBytes(stat.Size), formatNumber(stat.Count), colorReset)
+		m.renderFileList(b, files, m.ageFileCursor)
+		return
+	}
+
+	var total, cold, maxSize int64 = 0, 0, 1
+	for i, bucket := range m.insights.Ages {
+		stat := m.ageStat(bucket)
+		total += stat.Size
+		if i >= coldAgeBucket {
+			cold += stat.Size
+		}
+		if stat.Size > maxSize {
+			maxSize = stat.Size
+		}
+	}
+	fmt.Fprintf(b, "%sBy %s  |  Cold (6mo+): %s%s\n\n", colorGray, m.ageClockLabel(), humanizeBytes(cold), colorReset)
+
+	viewport := headedViewport(m.height)
+	start, end := m.ageCursor.window(len(m.insights.Ages), viewport)
+	for idx := start; idx < end; idx++ {
+		stat := m.ageStat(m.insights.Ages[idx])
+		percent := 0.0
+		if total > 0 {
+			percent = float64(stat.Size) / float64(total) * 100
+		}
+		bar := coloredProgressBar(stat.Size, maxSize, percent)
+		name := padName(m.insights.Ages[idx].Label, 12)
+
+		entryPrefix := "   "
+		nameSegment := name
+		numColor, percentColor, sizeColor := "", "", colorGray
+		if idx >= coldAgeBucket {
+			sizeColor = colorYellow
+		}
+		if idx == m.ageCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameSegment = fmt.Sprintf("%s%s%s", colorCyan, name, colorReset)
+			numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  🕰  %s %s%10s%s  %s%s files%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameSegment, sizeColor, humanizeBytes(stat.Size), colorReset,
+			colorGray, formatNumber(stat.Count), colorReset)
+	}
+	if folded := m.foldedSize(); folded > 0 {
+		fmt.Fprintf(b, "\n%s%s in folded dirs is not dated%s\n", colorGray, humanizeBytes(folded), colorReset)
+	}
+}
+
+// foldedSize is the du-measured total of folded directories, whose files carry no age.
+func (m model) foldedSize() int64 {
+	if m.insights == nil {
+		return 0
+	}
+	for _, stat := range m.insights.Categories {
+		if stat.Name == categoryOther {
+			return stat.Extensions[foldedExtension]
+		}
+	}
+	return 0
+}
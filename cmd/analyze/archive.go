@@ -0,0 +1,370 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archiveView is the read-only listing of an archive, browsed like a
+// directory. Entries below it get virtual paths under the archive's own path.
+type archiveView struct {
+	Path   string // The archive file
+	Packed int64  // Size of the archive file itself
+	root   *archiveNode
+	large  []fileEntry // Every file of at least minLargeFileSize, largest first
+}
+
+type archiveNode struct {
+	name     string
+	size     int64 // Uncompressed bytes, summed for directories
+	dir      bool
+	modTime  time.Time
+	children map[string]*archiveNode
+}
+
+type archiveLoadedMsg struct {
+	path string
+	view *archiveView
+	err  error
+}
+
+// isBrowsableArchive reports whether Enter can open path as a virtual directory.
+func isBrowsableArchive(path string) bool {
+	name := strings.ToLower(path)
+	for _, ext := range []string{".zip", ".jar", ".tar", ".tar.gz", ".tgz", ".tar.zst"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchive lists every member of the archive at path without extracting
+// anything. Compressed tarballs still have to be decompressed as a stream.
+func readArchive(ctx context.Context, archivePath string, filesScanned, bytesScanned *int64, currentPath *string) (*archiveView, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	view := &archiveView{
+		Path:   archivePath,
+		Packed: info.Size(),
+		root:   &archiveNode{dir: true, children: make(map[string]*archiveNode)},
+	}
+	add := func(name string, size int64, dir bool, modTime time.Time) {
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		if name == "" {
+			return
+		}
+		view.addMember(name, size, dir, modTime)
+		if !dir {
+			atomic.AddInt64(filesScanned, 1)
+			atomic.AddInt64(bytesScanned, size)
+			if currentPath != nil {
+				*currentPath = filepath.Join(archivePath, name)
+			}
+			if size >= minLargeFileSize {
+				view.large = append(view.large, fileEntry{Name: path.Base(name), Path: filepath.Join(archivePath, name), Size: size})
+			}
+		}
+	}
+
+	lower := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".jar"):
+		err = readZip(ctx, archivePath, add)
+	case strings.HasSuffix(lower, ".tar.zst"):
+		err = readZstdTar(ctx, archivePath, add)
+	default:
+		err = readTarFile(ctx, archivePath, add)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(view.large, func(i, j int) bool { return view.large[i].Size > view.large[j].Size })
+	return view, nil
+}
+
+// addMember files name into the tree, creating the directories a member
+// implies even when the archive does not list them.
+func (a *archiveView) addMember(name string, size int64, dir bool, modTime time.Time) {
+	node := a.root
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		last := i == len(parts)-1
+		child := node.children[part]
+		if child == nil {
+			child = &archiveNode{name: part, dir: !last || dir}
+			if child.dir {
+				child.children = make(map[string]*archiveNode)
+			}
+			node.children[part] = child
+		} else if !child.dir && (!last || dir) {
+			// A file member reused as a directory, as after tar -r
+			child.dir = true
+			child.children = make(map[string]*archiveNode)
+		}
+		if !dir {
+			child.size += size
+		}
+		if last {
+			child.modTime = modTime
+		}
+		node = child
+	}
+	if !dir {
+		a.root.size += size
+	}
+}
+
+type archiveMemberFunc func(name string, size int64, dir bool, modTime time.Time)
+
+func readZip(ctx context.Context, archivePath string, add archiveMemberFunc) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		add(file.Name, int64(file.UncompressedSize64), file.FileInfo().IsDir(), file.Modified)
+	}
+	return nil
+}
+
+func readTarFile(ctx context.Context, archivePath string, add archiveMemberFunc) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	lower := strings.ToLower(archivePath)
+	if !strings.HasSuffix(lower, ".gz") && !strings.HasSuffix(lower, ".tgz") {
+		// Plain tar: the reader seeks over member data instead of reading it
+		return readTar(ctx, file, add)
+	}
+	gz, err := gzip.NewReader(bufio.NewReaderSize(file, 1<<20))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	return readTar(ctx, gz, add)
+}
+
+// readZstdTar streams the archive through the zstd binary, as there is no
+// zstd decoder in the standard library.
+func readZstdTar(ctx context.Context, archivePath string, add archiveMemberFunc) error {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return errors.New("zstd is needed to read .tar.zst archives")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "zstd", "-q", "-d", "-c", archivePath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	readErr := readTar(ctx, stdout, add)
+	if readErr != nil {
+		cancel()
+	}
+	waitErr := cmd.Wait()
+	if readErr != nil {
+		return readErr
+	}
+	return waitErr
+}
+
+func readTar(ctx context.Context, r io.Reader, add archiveMemberFunc) error {
+	reader := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			add(header.Name, 0, true, header.ModTime)
+		case tar.TypeReg:
+			add(header.Name, header.Size, false, header.ModTime)
+		case tar.TypeXGlobalHeader:
+		default:
+			// Links and special files take no space of their own
+			add(header.Name, 0, false, header.ModTime)
+		}
+	}
+}
+
+// node returns the directory at a virtual path below the archive.
+func (a *archiveView) node(virtualPath string) *archiveNode {
+	rel, err := filepath.Rel(a.Path, virtualPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	node := a.root
+	if rel == "." {
+		return node
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if node = node.children[part]; node == nil || !node.dir {
+			return nil
+		}
+	}
+	return node
+}
+
+// listing returns the entries and large files of a virtual directory, shaped
+// like a scan result.
+func (a *archiveView) listing(virtualPath string) (scanResult, bool) {
+	node := a.node(virtualPath)
+	if node == nil {
+		return scanResult{}, false
+	}
+	result := scanResult{TotalSize: node.size}
+	for _, child := range node.children {
+		result.Entries = append(result.Entries, dirEntry{
+			Name:  child.name,
+			Path:  filepath.Join(virtualPath, child.name),
+			Size:  child.size,
+			IsDir: child.dir,
+		})
+	}
+	sort.Slice(result.Entries, func(i, j int) bool { return result.Entries[i].Size > result.Entries[j].Size })
+	if len(result.Entries) > maxEntries {
+		result.Entries = result.Entries[:maxEntries]
+	}
+	for _, file := range a.large {
+		if isPathWithin(file.Path, virtualPath) {
+			result.LargeFiles = append(result.LargeFiles, file)
+			if len(result.LargeFiles) == maxLargeFiles {
+				break
+			}
+		}
+	}
+	return result, true
+}
+
+// inArchive reports whether the view shows the contents of an archive.
+func (m model) inArchive() bool {
+	return m.archive != nil
+}
+
+// openArchive starts reading the archive at path. The current directory stays
+// on screen until the listing is ready.
+func (m model) openArchive(path string) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.archiveLoading = path
+	m.archiveCancel = cancel
+	m.status = "Reading archive..."
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	filesScanned, bytesScanned, currentPath := m.filesScanned, m.bytesScanned, m.currentPath
+	load := func() tea.Msg {
+		view, err := readArchive(ctx, path, filesScanned, bytesScanned, currentPath)
+		return archiveLoadedMsg{path: path, view: view, err: err}
+	}
+	return m, tea.Batch(load, tickCmd())
+}
+
+func (m model) handleArchiveLoaded(msg archiveLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.path != m.archiveLoading {
+		return m, nil
+	}
+	m.archiveLoading = ""
+	m.archiveCancel = nil
+	m.scanning = false
+	if msg.err != nil {
+		m.deleteNotice = fmt.Sprintf("Cannot read %s: %v", filepath.Base(msg.path), msg.err)
+		return m, nil
+	}
+	m.history = append(m.history, snapshotFromModel(m))
+	m.archive = msg.view
+	m.showLargeFiles = false
+	m.showArchiveDir(msg.path)
+	return m, nil
+}
+
+// showArchiveDir lists a directory inside the open archive.
+func (m *model) showArchiveDir(path string) {
+	result, ok := m.archive.listing(path)
+	if !ok {
+		return
+	}
+	m.path = path
+	m.entries = result.Entries
+	m.largeFiles = result.LargeFiles
+	m.totalSize = result.TotalSize
+	m.insights = nil
+	m.selected = 0
+	m.offset = 0
+	m.largeSelected = 0
+	m.largeOffset = 0
+	m.status = fmt.Sprintf("Archive %s, %s packed", displayPath(m.archive.Path), humanizeBytes(m.archive.Packed))
+}
+
+// leaveArchiveIfOutside drops the archive once navigation is back above it.
+func (m *model) leaveArchiveIfOutside() {
+	if m.archive != nil && m.path != m.archive.Path && !isPathWithin(m.path, m.archive.Path) {
+		m.archive = nil
+	}
+}
+
+// updateArchiveLoadingKey lets the user stop reading a large archive.
+func (m model) updateArchiveLoadingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.archiveCancel()
+		return m, tea.Quit
+	case "esc", "left", "h", "b":
+		m.archiveCancel()
+		m.archiveLoading = ""
+		m.archiveCancel = nil
+		m.scanning = false
+		m.status = "Cancelled"
+	}
+	return m, nil
+}
+
+// updateArchiveKey swallows the keys that act on real files while an archive
+// is browsed; navigation falls through to the main list handling.
+func (m model) updateArchiveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "q", "ctrl+c", "esc", "up", "k", "down", "j", "enter", "right", "l", "b", "left", "h", "L":
+		return m, nil, false
+	case "delete", "backspace", "x", "X", "z", "Z", " ":
+		m.deleteNotice = "Archive contents are read-only, extract them to change anything"
+	}
+	return m, nil, true
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// auditRecord is one line of the deletion log, written for every target the
+// analyzer removed or tried to remove.
+type auditRecord struct {
+	Time    time.Time `json:"time"`
+	Path    string    `json:"path"`
+	Bytes   int64     `json:"bytes"` // Apparent size actually removed
+	Items   int64     `json:"items"` // Files and directories removed
+	Method  string    `json:"method"`
+	Via     string    `json:"via"`               // "tui", "web" or "plan"
+	Removed bool      `json:"removed"`           // False when anything was left behind
+	Stopped bool      `json:"stopped,omitempty"` // Cancelled before finishing
+	Errors  []string  `json:"errors,omitempty"`  // "path: reason" for what could not be removed
+}
+
+// auditLogPath is analyze_history.jsonl under $XDG_STATE_HOME/mole when that
+// is set, otherwise next to the scan cache in ~/.cache/mole.
+func auditLogPath() (string, error) {
+	if state := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(state) {
+		dir := filepath.Join(state, "mole")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, historyLogFile), nil
+	}
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, historyLogFile), nil
+}
+
+// logDeletion appends one record per target of result. Logging is best effort
+// and never blocks a deletion.
+func logDeletion(method, via string, result deleteResult) error {
+	if len(result.Outcomes) == 0 {
+		return nil
+	}
+	logPath, err := auditLogPath()
+	if err != nil {
+		return err
+	}
+	var buf strings.Builder
+	now := time.Now()
+	for _, outcome := range result.Outcomes {
+		record := auditRecord{
+			Time:    now,
+			Path:    outcome.Path,
+			Bytes:   outcome.Bytes,
+			Items:   outcome.Items,
+			Method:  method,
+			Via:     via,
+			Removed: outcome.Removed,
+			Stopped: result.Cancelled && !outcome.Removed,
+		}
+		for _, failure := range outcome.Failures {
+			record.Errors = append(record.Errors, failure.Path+": "+failure.Reason)
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	// One append per batch keeps lines whole when the TUI and --serve write at once
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, buf.String()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readAuditLog returns every record in the log, oldest first. Lines that do not
+// parse are skipped so one torn write cannot hide the rest.
+func readAuditLog() ([]auditRecord, string, error) {
+	logPath, err := auditLogPath()
+	if err != nil {
+		return nil, "", err
+	}
+	file, err := os.Open(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, logPath, nil
+		}
+		return nil, logPath, err
+	}
+	defer file.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+	for scanner.Scan() {
+		var record auditRecord
+		if json.Unmarshal(scanner.Bytes(), &record) == nil && record.Path != "" {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, logPath, scanner.Err()
+}
+
+// runHistory prints the deletion log grouped by day, newest first, followed by
+// the space reclaimed per month. With filter only paths below it are shown.
+func runHistory(out io.Writer, filter string) error {
+	records, logPath, err := readAuditLog()
+	if err != nil {
+		return err
+	}
+	if filter != "" {
+		abs, err := filepath.Abs(filter)
+		if err != nil {
+			return err
+		}
+		kept := records[:0]
+		for _, record := range records {
+			if record.Path == abs || isPathWithin(record.Path, abs) {
+				kept = append(kept, record)
+			}
+		}
+		records = kept
+	}
+	fmt.Fprintf(out, "Deletion history  %s\n", displayPath(logPath))
+	if len(records) == 0 {
+		fmt.Fprintln(out, "\nNothing deleted yet")
+		return nil
+	}
+
+	type period struct {
+		label string
+		bytes int64
+		count int
+	}
+	var days, months []*period
+	dayRecords := make(map[*period][]auditRecord)
+	for _, record := range records {
+		local := record.Time.Local()
+		day := local.Format("Mon 2006-01-02")
+		if len(days) == 0 || days[len(days)-1].label != day {
+			days = append(days, &period{label: day})
+		}
+		month := local.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].label != month {
+			months = append(months, &period{label: month})
+		}
+		for _, p := range []*period{days[len(days)-1], months[len(months)-1]} {
+			p.bytes += record.Bytes
+			p.count++
+		}
+		dayRecords[days[len(days)-1]] = append(dayRecords[days[len(days)-1]], record)
+	}
+
+	var total int64
+	for i := len(days) - 1; i >= 0; i-- {
+		day := days[i]
+		total += day.bytes
+		fmt.Fprintf(out, "\n%-56s %10s\n", day.label, humanizeBytes(day.bytes))
+		entries := dayRecords[day]
+		for j := len(entries) - 1; j >= 0; j-- {
+			record := entries[j]
+			note := ""
+			switch {
+			case record.Stopped:
+				note = "  stopped"
+			case len(record.Errors) > 0:
+				note = fmt.Sprintf("  %d errors", len(record.Errors))
+			case !record.Removed:
+				note = "  incomplete"
+			}
+			fmt.Fprintf(out, "  %s  %-6s  %-40s %10s  %8s items%s\n",
+				record.Time.Local().Format("15:04"), record.Method,
+				truncateMiddle(displayPath(record.Path), 40), humanizeBytes(record.Bytes),
+				formatNumber(record.Items), note)
+		}
+	}
+
+	var largest int64
+	for _, month := range months {
+		largest = max(largest, month.bytes)
+	}
+	fmt.Fprintf(out, "\nReclaimed per month\n")
+	for _, month := range months {
+		width := 0
+		if largest > 0 {
+			width = int(float64(month.bytes) / float64(largest) * float64(barWidth))
+		}
+		fmt.Fprintf(out, "  %s  %10s  %-*s  %d deletions\n", month.label, humanizeBytes(month.bytes),
+			barWidth, strings.Repeat("█", width), month.count)
+	}
+	fmt.Fprintf(out, "\nTotal %s in %d deletions\n", humanizeBytes(total), len(records))
+	return nil
+}
@@ -29,6 +29,7 @@ func snapshotFromModel(m model) historyEntry {
 		Entries:       cloneDirEntries(m.entries),
 		LargeFiles:    cloneFileEntries(m.largeFiles),
 		TotalSize:     m.totalSize,
+		Insights:      m.insights,
 		Selected:      m.selected,
 		EntryOffset:   m.offset,
 		LargeSelected: m.largeSelected,
@@ -230,6 +231,8 @@ func loadCacheFromDisk(path string) (*cacheEntry, error) {
 		return nil, fmt.Errorf("cache expired: too old")
 	}
 
+	// Sibling files or git ignore rules may have changed since the scan
+	markCleanable(entry.Entries)
 	return &entry, nil
 }
 
@@ -248,6 +251,7 @@ func saveCacheToDisk(path string, result scanResult) error {
 		Entries:    result.Entries,
 		LargeFiles: result.LargeFiles,
 		TotalSize:  result.TotalSize,
+		Insights:   result.Insights,
 		ModTime:    info.ModTime(),
 		ScanTime:   time.Now(),
 	}
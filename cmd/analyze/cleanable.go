@@ -1,10 +1,105 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
+// cleanableRule marks a directory name as rebuildable. Conditions narrow it down:
+// every condition that is set must hold, each list matches if any item exists.
+// Several rules for one name are alternatives.
+type cleanableRule struct {
+	Name       string
+	Siblings   []string // Files next to the directory, e.g. Cargo.toml for target
+	Contains   []string // Files inside the directory, e.g. pyvenv.cfg for venv
+	GitIgnored bool     // Directory must be ignored by git
+}
+
+// Project dependency and build directories
+// These are safe to delete manually but mo clean won't touch them
+var defaultCleanableRules = []cleanableRule{
+	// JavaScript/Node dependencies
+	{Name: "node_modules"},
+	{Name: "bower_components"},
+	{Name: ".yarn", GitIgnored: true}, // Yarn Berry may commit releases and zero-install cache
+	{Name: ".pnpm-store"},
+
+	// Python dependencies and outputs
+	{Name: "venv", Contains: []string{"pyvenv.cfg"}},
+	{Name: ".venv", Contains: []string{"pyvenv.cfg"}},
+	{Name: "virtualenv", Contains: []string{"pyvenv.cfg"}},
+	{Name: "__pycache__"},
+	{Name: ".pytest_cache"},
+	{Name: ".mypy_cache"},
+	{Name: ".ruff_cache"},
+	{Name: ".tox"},
+	{Name: ".eggs"},
+	{Name: "htmlcov"},            // Coverage reports
+	{Name: ".ipynb_checkpoints"}, // Jupyter checkpoints
+
+	// Ruby/PHP dependencies; Go modules may commit vendor/
+	{Name: "vendor", Siblings: []string{"Gemfile", "composer.json"}},
+	{Name: "vendor", GitIgnored: true},
+	{Name: ".bundle", Siblings: []string{"Gemfile"}},
+
+	// Java/Kotlin/Scala/Rust
+	{Name: "target", Siblings: []string{"Cargo.toml", "pom.xml"}},
+	{Name: ".gradle"}, // Project-level Gradle cache
+	{Name: "build", Siblings: []string{"build.gradle", "build.gradle.kts", "pubspec.yaml"}},
+	{Name: "build", GitIgnored: true},
+	{Name: "out", GitIgnored: true}, // IntelliJ IDEA build output
+	{Name: "dist", GitIgnored: true},
+
+	// Build outputs (can be rebuilt)
+	{Name: ".next"},
+	{Name: ".nuxt"},
+	{Name: ".output"},
+	{Name: ".parcel-cache"},
+	{Name: ".turbo"},
+	{Name: ".vite"}, // Vite cache
+	{Name: ".nx"},   // Nx cache
+	{Name: "coverage"},
+	{Name: ".nyc_output"}, // NYC coverage
+
+	// Frontend framework outputs
+	{Name: ".angular"},    // Angular CLI cache
+	{Name: ".svelte-kit"}, // SvelteKit build
+	{Name: ".astro"},      // Astro cache
+	{Name: ".docusaurus"}, // Docusaurus build
+
+	// Dart/Elixir
+	{Name: ".dart_tool"},
+	{Name: "_build", Siblings: []string{"mix.exs"}},
+	{Name: "deps", Siblings: []string{"mix.exs"}},
+
+	// iOS/macOS development
+	{Name: "DerivedData"},
+	{Name: "Pods", Siblings: []string{"Podfile"}},
+	{Name: ".build", Siblings: []string{"Package.swift"}},
+	{Name: "Carthage", Siblings: []string{"Cartfile"}},
+
+	// Other tools
+	{Name: ".terraform"}, // Terraform plugins
+}
+
+var (
+	cleanableRulesOnce sync.Once
+	cleanableRules     map[string][]cleanableRule
+)
+
+// markCleanable decides the 🧹 badge of every directory entry. It may run git
+// and stat siblings, so it belongs in scan and load commands, never in View.
+func markCleanable(entries []dirEntry) {
+	for i := range entries {
+		entries[i].Cleanable = entries[i].IsDir && isCleanableDir(entries[i].Path)
+	}
+}
+
 // isCleanableDir checks if a directory is safe to manually delete
 // but NOT cleaned by mo clean (so user might want to delete it manually)
 func isCleanableDir(path string) bool {
@@ -18,17 +113,113 @@ func isCleanableDir(path string) bool {
 		return false
 	}
 
-	baseName := filepath.Base(path)
+	for _, rule := range loadCleanableRules()[filepath.Base(path)] {
+		if cleanableRuleMatches(rule, path) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Only mark project dependencies and build outputs
-	// These are safe to delete but mo clean won't touch them
-	if projectDependencyDirs[baseName] {
-		return true
+func cleanableRuleMatches(rule cleanableRule, path string) bool {
+	if len(rule.Siblings) > 0 && !anyPathExists(filepath.Dir(path), rule.Siblings) {
+		return false
+	}
+	if len(rule.Contains) > 0 && !anyPathExists(path, rule.Contains) {
+		return false
 	}
+	if rule.GitIgnored && !isGitIgnored(path) {
+		return false
+	}
+	return true
+}
 
+func anyPathExists(dir string, names []string) bool {
+	for _, name := range names {
+		if _, err := os.Lstat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
 	return false
 }
 
+// isGitIgnored asks git whether path is ignored; outside a work tree it is not.
+func isGitIgnored(path string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCheckTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "-C", filepath.Dir(path), "check-ignore", "-q", filepath.Base(path))
+	return cmd.Run() == nil
+}
+
+// loadCleanableRules merges the built-in rules with ~/.config/mole/analyze_cleanable.
+func loadCleanableRules() map[string][]cleanableRule {
+	cleanableRulesOnce.Do(func() {
+		cleanableRules = make(map[string][]cleanableRule)
+		for _, rule := range defaultCleanableRules {
+			cleanableRules[rule.Name] = append(cleanableRules[rule.Name], rule)
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return
+		}
+		file, err := os.Open(filepath.Join(home, ".config", "mole", cleanableConfigFile))
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if name, ok := strings.CutPrefix(line, "-"); ok {
+				// "-name" drops every built-in rule for that name
+				delete(cleanableRules, strings.TrimSpace(name))
+				continue
+			}
+			if rule, ok := parseCleanableRule(line); ok {
+				cleanableRules[rule.Name] = append(cleanableRules[rule.Name], rule)
+			}
+		}
+	})
+	return cleanableRules
+}
+
+// parseCleanableRule reads "name [sibling=a,b] [contains=c] [ignored]".
+func parseCleanableRule(line string) (cleanableRule, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.ContainsRune(fields[0], '/') {
+		return cleanableRule{}, false
+	}
+	rule := cleanableRule{Name: fields[0]}
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "sibling", "siblings":
+			rule.Siblings = append(rule.Siblings, splitRuleList(value)...)
+		case "contains":
+			rule.Contains = append(rule.Contains, splitRuleList(value)...)
+		case "ignored":
+			rule.GitIgnored = true
+		default:
+			return cleanableRule{}, false
+		}
+	}
+	return rule, true
+}
+
+func splitRuleList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // isHandledByMoClean checks if this path will be cleaned by mo clean
 func isHandledByMoClean(path string) bool {
 	// Paths that mo clean handles (from clean.sh)
@@ -49,64 +240,3 @@ func isHandledByMoClean(path string) bool {
 
 	return false
 }
-
-// Project dependency and build directories
-// These are safe to delete manually but mo clean won't touch them
-var projectDependencyDirs = map[string]bool{
-	// JavaScript/Node dependencies
-	"node_modules":     true,
-	"bower_components": true,
-	".yarn":            true, // Yarn local cache
-	".pnpm-store":      true, // pnpm store
-
-	// Python dependencies and outputs
-	"venv":                 true,
-	".venv":                true,
-	"virtualenv":           true,
-	"__pycache__":          true,
-	".pytest_cache":        true,
-	".mypy_cache":          true,
-	".ruff_cache":          true,
-	".tox":                 true,
-	".eggs":                true,
-	"htmlcov":              true, // Coverage reports
-	".ipynb_checkpoints":   true, // Jupyter checkpoints
-
-	// Ruby dependencies
-	"vendor":  true,
-	".bundle": true,
-
-	// Java/Kotlin/Scala
-	".gradle": true, // Project-level Gradle cache
-	"out":     true, // IntelliJ IDEA build output
-
-	// Build outputs (can be rebuilt)
-	"build":         true,
-	"dist":          true,
-	"target":        true,
-	".next":         true,
-	".nuxt":         true,
-	".output":       true,
-	".parcel-cache": true,
-	".turbo":        true,
-	".vite":         true, // Vite cache
-	".nx":           true, // Nx cache
-	"coverage":      true,
-	".coverage":     true,
-	".nyc_output":   true, // NYC coverage
-
-	// Frontend framework outputs
-	".angular":     true, // Angular CLI cache
-	".svelte-kit":  true, // SvelteKit build
-	".astro":       true, // Astro cache
-	".docusaurus":  true, // Docusaurus build
-
-	// iOS/macOS development
-	"DerivedData": true,
-	"Pods":        true,
-	".build":      true,
-	"Carthage":    true,
-
-	// Other tools
-	".terraform": true, // Terraform plugins
-}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"compress/flate"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compressEstimate projects what compressing a path would save, from a
+// bounded sample of its files compressed with deflate at its fastest level.
+type compressEstimate struct {
+	Path       string
+	Total      int64 // Bytes of the files considered
+	Saved      int64 // Projected savings
+	Sampled    int64 // Bytes actually compressed
+	Categories []compressCategory
+	Partial    bool // The walk stopped at maxCompressWalk files
+	Err        error
+}
+
+// compressCategory is the estimate for one file type category.
+type compressCategory struct {
+	Name  string
+	Size  int64
+	Saved int64
+}
+
+type compressMsg struct {
+	estimate *compressEstimate
+}
+
+// compressSlots bounds how many estimates read from disk at once.
+var compressSlots = make(chan struct{}, maxCompressJobs)
+
+type sampleFile struct {
+	path string
+	size int64
+}
+
+func estimateCompression(path string) *compressEstimate {
+	compressSlots <- struct{}{}
+	defer func() { <-compressSlots }()
+
+	estimate := &compressEstimate{Path: path}
+	byCategory := make(map[string][]sampleFile)
+	var walked int
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == path {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if walked >= maxCompressWalk {
+			estimate.Partial = true
+			return filepath.SkipAll
+		}
+		walked++
+		info, err := d.Info()
+		if err != nil || info.Size() == 0 {
+			return nil
+		}
+		category := fileCategory(strings.ToLower(filepath.Ext(p)), info)
+		byCategory[category] = append(byCategory[category], sampleFile{path: p, size: info.Size()})
+		estimate.Total += info.Size()
+		return nil
+	})
+	if err != nil {
+		estimate.Err = err
+		return estimate
+	}
+
+	counter := &countingWriter{}
+	compressor, _ := flate.NewWriter(counter, flate.BestSpeed)
+	buf := make([]byte, compressChunkSize)
+	for name, files := range byCategory {
+		stat := compressCategory{Name: name}
+		for _, file := range files {
+			stat.Size += file.size
+		}
+		var raw, packed int64
+		for _, file := range pickSamples(files, compressSamplesPerCategory) {
+			n, compressed := compressSample(file, buf, compressor, counter)
+			raw += n
+			packed += compressed
+		}
+		estimate.Sampled += raw
+		if raw > 0 && packed < raw {
+			stat.Saved = int64(float64(stat.Size) * (1 - float64(packed)/float64(raw)))
+		}
+		estimate.Saved += stat.Saved
+		estimate.Categories = append(estimate.Categories, stat)
+	}
+	sort.Slice(estimate.Categories, func(i, j int) bool {
+		return estimate.Categories[i].Size > estimate.Categories[j].Size
+	})
+	return estimate
+}
+
+// pickSamples spreads up to n picks evenly over files sorted by size, so both
+// the few big files and the many small ones are represented.
+func pickSamples(files []sampleFile, n int) []sampleFile {
+	if len(files) <= n {
+		return files
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].size > files[j].size })
+	picked := make([]sampleFile, 0, n)
+	for i := 0; i < n; i++ {
+		picked = append(picked, files[i*(len(files)-1)/(n-1)])
+	}
+	return picked
+}
+
+// compressSample compresses one chunk from the middle of file, past any
+// header, and returns the raw and compressed byte counts.
+func compressSample(file sampleFile, buf []byte, compressor *flate.Writer, counter *countingWriter) (int64, int64) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	offset := int64(0)
+	if file.size > int64(len(buf)) {
+		offset = (file.size - int64(len(buf))) / 2
+	}
+	n, err := f.ReadAt(buf, offset)
+	if n == 0 || (err != nil && err != io.EOF) {
+		return 0, 0
+	}
+	counter.n = 0
+	compressor.Reset(counter)
+	_, _ = compressor.Write(buf[:n])
+	_ = compressor.Close()
+	return int64(n), counter.n
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// requestCompress starts estimates for the listed entries that have none yet.
+func (m model) requestCompress() tea.Cmd {
+	if !m.showCompress || m.scanning || m.inOverviewMode() || m.inArchive() || m.compress == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, entry := range m.entries {
+		if m.compress[entry.Path] != nil || m.compressLoading[entry.Path] {
+			continue
+		}
+		m.compressLoading[entry.Path] = true
+		path := entry.Path
+		cmds = append(cmds, func() tea.Msg {
+			return compressMsg{estimate: estimateCompression(path)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// compressLabel is the savings column next to an entry's size.
+func (m model) compressLabel(path string) string {
+	if !m.showCompress || m.inArchive() {
+		return ""
+	}
+	estimate := m.compress[path]
+	switch {
+	case estimate == nil:
+		return fmt.Sprintf("%s%6s%s", colorGray, "…", colorReset)
+	case estimate.Err != nil || estimate.Total == 0:
+		return fmt.Sprintf("%s%6s%s", colorGray, "-", colorReset)
+	}
+	percent := math.Round(float64(estimate.Saved) / float64(estimate.Total) * 100)
+	if percent < 1 {
+		return fmt.Sprintf("%s%6s%s", colorGray, "0%", colorReset)
+	}
+	color := colorGray
+	if percent >= 20 {
+		color = colorGreen
+	}
+	return fmt.Sprintf("%s%5.0f%%%s", color, -percent, colorReset)
+}
+
+// renderCompressDetails is the per type breakdown shown in the details pane.
+func (m model) renderCompressDetails(b *strings.Builder, path string, width int) {
+	estimate := m.compress[path]
+	if estimate == nil || estimate.Err != nil || estimate.Total == 0 {
+		return
+	}
+	partial := ""
+	if estimate.Partial {
+		partial = ", partial"
+	}
+	fmt.Fprintf(b, "\n%sCompressed ~%s, saves ~%s%s%s\n", colorGray,
+		humanizeBytes(estimate.Total-estimate.Saved), humanizeBytes(estimate.Saved), partial, colorReset)
+	for _, category := range estimate.Categories {
+		percent := 0.0
+		if category.Size > 0 {
+			percent = math.Round(float64(category.Saved) / float64(category.Size) * 100)
+		}
+		if percent > 0 {
+			percent = -percent
+		}
+		fmt.Fprintf(b, "%s %s%10s%s %4.0f%%\n",
+			padName(truncateMiddle(category.Name, width-18), width-18),
+			colorGray, humanizeBytes(category.Size), colorReset, percent)
+	}
+}
+
+// resetCompress drops estimates made before the tree changed. The column
+// stays off until it is toggled on, so nothing is allocated for it.
+func (m *model) resetCompress() {
+	if !m.showCompress && m.compress == nil {
+		return
+	}
+	m.compress = make(map[string]*compressEstimate)
+	m.compressLoading = make(map[string]bool)
+}
@@ -5,23 +5,54 @@ import "time"
 const (
 	maxEntries            = 30
 	maxLargeFiles         = 30
+	maxCategoryFiles      = 30  // Largest files kept per file type category
+	maxTopDirs            = 50  // Directories kept per ranking in the top directories view
+	maxReportFiles        = 100 // Largest files listed in the HTML report
+	reportTreeDepth       = 2   // Directory levels below the root in the HTML report treemap
+	maxDetailsFiles       = 5   // Largest descendants listed in the details pane
+	maxScanErrors         = 200 // Unreadable paths kept per scan, the rest are only counted
+	maxDeleteFailures     = 200 // Undeletable paths kept per deletion, the rest are only counted
+	defaultConfirmSize    = 10 << 30 // Deleting this much needs the name typed, see MO_ANALYZE_CONFIRM_SIZE
+	detailsPaneWidth      = 46  // Columns of the details pane, border included
 	barWidth              = 24
 	minLargeFileSize      = 100 << 20 // 100 MB
 	defaultViewport       = 12        // Default viewport when terminal height is unknown
 	overviewCacheTTL      = 7 * 24 * time.Hour // 7 days
 	overviewCacheFile     = "overview_sizes.json"
+	shortcutConfigFile    = "analyze_shortcuts" // Under ~/.config/mole
+	cleanableConfigFile   = "analyze_cleanable" // Under ~/.config/mole
+	historyLogFile        = "analyze_history.jsonl" // Deletion log, see auditLogPath
 	duTimeout             = 60 * time.Second // Increased for large directories
+	dirSizeTimeout        = 5 * time.Minute  // calculateDirSizeFast gives up after this, leaving a partial size
 	mdlsTimeout           = 5 * time.Second
+	gitCheckTimeout       = 2 * time.Second
+	maxGuardWalkDirs      = 10000 // Directories searched for nested git repositories before a delete
 	maxConcurrentOverview = 3                // Scan up to 3 overview dirs concurrently
 	batchUpdateSize       = 100              // Batch atomic updates every N items
 	cacheModTimeGrace     = 30 * time.Minute // Ignore minor directory mtime bumps
+	detailsDebounce       = 150 * time.Millisecond // Selection must rest this long before details load
 
 	// Worker pool configuration
 	minWorkers         = 8                // Minimum workers for better I/O throughput
 	maxWorkers         = 64               // Maximum workers to avoid excessive goroutines
 	cpuMultiplier      = 2                // Worker multiplier per CPU core for I/O-bound operations
 	maxDirWorkers      = 16               // Maximum concurrent subdirectory scans
+	maxDeleteWorkers   = 32               // Maximum concurrent directory removals
 	openCommandTimeout = 10 * time.Second // Timeout for open/reveal commands
+
+	// Compression estimate (% column)
+	maxCompressJobs            = 4         // Entries estimated at once
+	maxCompressWalk            = 20000     // Files considered per entry
+	compressSamplesPerCategory = 16        // Files compressed per file type
+	compressChunkSize          = 128 << 10 // Bytes compressed per sampled file
+
+	// Git repository breakdown (G)
+	maxGitBlobs         = 20   // Largest history blobs listed
+	gitLooseObjectsHint = 6700 // Loose objects before suggesting git gc, git's own gc.auto default
+
+	// Headless cache warming (analyze --warm)
+	warmNiceLevel = 10          // Scheduling priority for background runs
+	warmLockFile  = "warm.lock" // Prevents overlapping timer runs
 )
 
 var foldDirs = map[string]bool{
@@ -174,6 +205,11 @@ var skipSystemDirs = map[string]bool{
 	".fseventsd":              true,
 	".DocumentRevisions-V100": true,
 	".TemporaryItems":         true,
+
+	// Linux pseudo filesystems
+	"proc": true,
+	"sys":  true,
+	"run":  true,
 }
 
 var skipExtensions = map[string]bool{
@@ -0,0 +1,65 @@
+package main
+
+// listCursor tracks selection and scroll offset for the secondary list views.
+type listCursor struct {
+	Selected int
+	Offset   int
+}
+
+func (c *listCursor) up() {
+	if c.Selected > 0 {
+		c.Selected--
+		if c.Selected < c.Offset {
+			c.Offset = c.Selected
+		}
+	}
+}
+
+func (c *listCursor) down(count, viewport int) {
+	if c.Selected < count-1 {
+		c.Selected++
+		if c.Selected >= c.Offset+viewport {
+			c.Offset = c.Selected - viewport + 1
+		}
+	}
+}
+
+func (c *listCursor) clamp(count, viewport int) {
+	if c.Selected >= count {
+		c.Selected = count - 1
+	}
+	if c.Selected < 0 {
+		c.Selected = 0
+	}
+	if c.Offset > c.Selected {
+		c.Offset = c.Selected
+	}
+	if c.Selected >= c.Offset+viewport {
+		c.Offset = c.Selected - viewport + 1
+	}
+	if c.Offset < 0 {
+		c.Offset = 0
+	}
+}
+
+// window returns the visible [start, end) range for a list of count rows.
+func (c listCursor) window(count, viewport int) (int, int) {
+	start := c.Offset
+	if start < 0 {
+		start = 0
+	}
+	end := start + viewport
+	if end > count {
+		end = count
+	}
+	return start, end
+}
+
+// headedViewport is the row budget of a list drawn below a heading line and a blank line.
+func headedViewport(termHeight int) int {
+	viewport := calculateViewport(termHeight, true) - 2
+	if viewport < 1 {
+		viewport = 1
+	}
+	return viewport
+}
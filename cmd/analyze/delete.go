@@ -1,69 +1,291 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func deletePathCmd(path string, counter *int64) tea.Cmd {
+// deleteFailure is a path that was left in place, with the reason.
+type deleteFailure struct {
+	Path   string
+	Reason string
+}
+
+// deleteProgress is shared by the deletion workers and the view. The counters
+// are updated atomically, failures under mu.
+type deleteProgress struct {
+	items int64 // Files and directories removed
+	bytes int64 // Apparent size of the removed files
+
+	mu           sync.Mutex
+	failures     []deleteFailure // First maxDeleteFailures only
+	failureCount int64
+}
+
+func (p *deleteProgress) removed(size int64) {
+	atomic.AddInt64(&p.items, 1)
+	atomic.AddInt64(&p.bytes, size)
+}
+
+func (p *deleteProgress) fail(path string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failureCount++
+	if len(p.failures) < maxDeleteFailures {
+		p.failures = append(p.failures, deleteFailure{Path: path, Reason: scanErrorReason(err)})
+	}
+}
+
+func (p *deleteProgress) snapshot() (items, bytes int64) {
+	return atomic.LoadInt64(&p.items), atomic.LoadInt64(&p.bytes)
+}
+
+// deleteResult is what a finished or cancelled deletion left behind.
+type deleteResult struct {
+	Targets      []string // Everything that was asked for
+	Removed      []string // Targets that are completely gone
+	Items        int64
+	Bytes        int64
+	Cancelled    bool
+	Failures     []deleteFailure
+	FailureCount int64
+	Outcomes     []deleteOutcome // One per target, in order
+}
+
+// deleteOutcome is what happened to a single target.
+type deleteOutcome struct {
+	Path     string
+	Items    int64
+	Bytes    int64
+	Removed  bool
+	Failures []deleteFailure // Capped like deleteResult.Failures
+}
+
+// deletePathsCmd removes paths in the background. Cancelling ctx stops the
+// workers between files; whatever was not reached stays on disk.
+func deletePathsCmd(ctx context.Context, paths []string, progress *deleteProgress) tea.Cmd {
 	return func() tea.Msg {
-		count, err := deletePathWithProgress(path, counter)
-		return deleteProgressMsg{
-			done:  true,
-			err:   err,
-			count: count,
-			path:  path,
+		result := deletePaths(ctx, paths, progress)
+		_ = logDeletion("delete", "tui", result)
+		return deleteProgressMsg{done: true, result: result}
+	}
+}
+
+// deletePaths removes every path with a bounded pool of workers, walking
+// directories in parallel and removing each one once its children are gone.
+func deletePaths(ctx context.Context, paths []string, progress *deleteProgress) deleteResult {
+	if progress == nil {
+		progress = &deleteProgress{}
+	}
+	workers := runtime.NumCPU() * cpuMultiplier
+	if workers > maxDeleteWorkers {
+		workers = maxDeleteWorkers
+	}
+	d := &treeDeleter{ctx: ctx, progress: progress, sem: make(chan struct{}, workers)}
+
+	result := deleteResult{Targets: paths}
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		items, bytes := progress.snapshot()
+		progress.mu.Lock()
+		failed := len(progress.failures)
+		progress.mu.Unlock()
+
+		outcome := deleteOutcome{Path: path, Removed: d.remove(path)}
+		outcome.Items, outcome.Bytes = progress.snapshot()
+		outcome.Items -= items
+		outcome.Bytes -= bytes
+		progress.mu.Lock()
+		outcome.Failures = progress.failures[failed:]
+		progress.mu.Unlock()
+		result.Outcomes = append(result.Outcomes, outcome)
+		if outcome.Removed {
+			result.Removed = append(result.Removed, path)
 		}
 	}
+	result.Items, result.Bytes = progress.snapshot()
+	result.Cancelled = ctx.Err() != nil
+	progress.mu.Lock()
+	result.Failures = progress.failures
+	result.FailureCount = progress.failureCount
+	progress.mu.Unlock()
+	return result
 }
 
-func deletePathWithProgress(root string, counter *int64) (int64, error) {
-	var count int64
-	var firstErr error
+type treeDeleter struct {
+	ctx      context.Context
+	progress *deleteProgress
+	sem      chan struct{} // Free slots for extra directory workers
+}
 
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			// Skip permission errors but continue walking
-			if os.IsPermission(err) {
-				if firstErr == nil {
-					firstErr = err
-				}
-				return filepath.SkipDir
+// remove deletes path and everything below it, reporting whether it is gone.
+// A directory is only removed when all of its children were, so a cancelled or
+// failed run never leaves a half-removed entry behind a missing parent.
+func (d *treeDeleter) remove(path string) bool {
+	if d.ctx.Err() != nil {
+		return false
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return true
+		}
+		d.progress.fail(path, err)
+		return false
+	}
+	if !info.IsDir() {
+		return d.removeFile(path, info.Size())
+	}
+
+	if info.Mode().Perm()&0200 == 0 {
+		// Read-only trees such as the Go module cache need write access to empty them
+		_ = os.Chmod(path, info.Mode().Perm()|0200)
+	}
+	children, err := os.ReadDir(path)
+	if err != nil {
+		d.progress.fail(path, err)
+		return false
+	}
+	var wg sync.WaitGroup
+	var incomplete atomic.Bool
+	for _, child := range children {
+		if d.ctx.Err() != nil {
+			incomplete.Store(true)
+			break
+		}
+		childPath := filepath.Join(path, child.Name())
+		if !child.IsDir() {
+			var size int64
+			if childInfo, err := child.Info(); err == nil {
+				size = childInfo.Size()
 			}
-			// For other errors, record and continue
-			if firstErr == nil {
-				firstErr = err
+			if !d.removeFile(childPath, size) {
+				incomplete.Store(true)
 			}
-			return nil
+			continue
 		}
-
-		if !d.IsDir() {
-			if removeErr := os.Remove(path); removeErr == nil {
-				count++
-				if counter != nil {
-					atomic.StoreInt64(counter, count)
+		// Hand the subdirectory to a new worker if one is free, else do it here
+		select {
+		case d.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-d.sem }()
+				if !d.remove(childPath) {
+					incomplete.Store(true)
 				}
-			} else if firstErr == nil {
-				// Record first deletion error
-				firstErr = removeErr
+			}()
+		default:
+			if !d.remove(childPath) {
+				incomplete.Store(true)
 			}
 		}
+	}
+	wg.Wait()
+	if incomplete.Load() {
+		return false
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		d.progress.fail(path, err)
+		return false
+	}
+	d.progress.removed(0)
+	return true
+}
+
+func (d *treeDeleter) removeFile(path string, size int64) bool {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		d.progress.fail(path, err)
+		return false
+	}
+	d.progress.removed(size)
+	return true
+}
 
-		return nil
-	})
+// deleteSummary is the notice shown when a deletion was stopped or left paths behind.
+func deleteSummary(result deleteResult) string {
+	summary := fmt.Sprintf("Removed %s items, %s", formatNumber(result.Items), humanizeBytes(result.Bytes))
+	if result.Cancelled {
+		summary = "Stopped. " + summary + ", the rest is still in place"
+	}
+	if result.FailureCount > 0 {
+		summary += fmt.Sprintf(". %s paths could not be deleted", formatNumber(result.FailureCount))
+	}
+	return summary
+}
 
-	if err != nil {
-		return count, err
+// updateDeletingKey only lets the running deletion be stopped or the program quit.
+func (m model) updateDeletingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		if m.deleteCancel != nil {
+			m.deleteCancel()
+		}
+		return m, tea.Quit
+	case "esc":
+		if m.deleteCancel != nil {
+			m.deleteCancel()
+			m.deleteCancel = nil
+			m.status = "Stopping deletion..."
+		}
 	}
+	return m, nil
+}
 
-	if err := os.RemoveAll(root); err != nil {
-		return count, err
+func (m model) updateDeleteFailuresKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "b", "left", "h", "enter":
+		m.deleteFailures = nil
+		m.deleteFailureCount = 0
+	case "up", "k":
+		m.failureCursor.up()
+	case "down", "j":
+		m.failureCursor.down(len(m.deleteFailures), headedViewport(m.height))
+	case "f", "F":
+		if m.failureCursor.Selected < len(m.deleteFailures) {
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = revealPathCommand(ctx, path).Run()
+			}(m.deleteFailures[m.failureCursor.Selected].Path)
+		}
 	}
+	return m, nil
+}
 
-	// Return the first error encountered during deletion if any
-	return count, firstErr
+func (m model) renderDeleteFailures(b *strings.Builder) {
+	shown := ""
+	if int64(len(m.deleteFailures)) < m.deleteFailureCount {
+		shown = fmt.Sprintf(", first %d shown", len(m.deleteFailures))
+	}
+	fmt.Fprintf(b, "%s%s paths could not be deleted%s, everything else is gone%s\n\n", colorGray, formatNumber(m.deleteFailureCount), shown, colorReset)
+
+	start, end := m.failureCursor.window(len(m.deleteFailures), headedViewport(m.height))
+	for idx := start; idx < end; idx++ {
+		entry := m.deleteFailures[idx]
+		entryPrefix := "   "
+		nameColor, numColor := "", ""
+		if idx == m.failureCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, numColor = colorCyan, colorCyan
+		}
+		name := padName(truncateMiddle(relativeToScan(m.path, entry.Path), 60), 60)
+		fmt.Fprintf(b, "%s%s%2d.%s ✗  %s%s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset,
+			nameColor, name, colorReset, colorRed, entry.Reason, colorReset)
+	}
 }
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deleteCheck is the guard verdict for a deletion target.
+type deleteCheck struct {
+	Refused  string   // Why the target must not be deleted, empty if allowed
+	Warnings []string // Reasons to make the user type the name before deleting
+}
+
+// protectedPaths can never be deleted from the analyzer.
+var protectedPaths = []string{
+	"/", "/System", "/Library", "/Applications", "/Users", "/Volumes", "/private", "/home",
+	"/usr", "/etc", "/bin", "/sbin", "/var", "/opt", "/boot", "/lib", "/lib64", "/dev", "/proc", "/sys", "/mnt", "/media",
+}
+
+// systemTrees hold files the OS depends on; anything below them needs a typed confirmation.
+var systemTrees = []string{
+	"/System", "/usr", "/etc", "/bin", "/sbin", "/boot", "/lib", "/lib64", "/private/etc", "/private/var/db",
+}
+
+// virtualTrees are kernel file systems where nothing is deletable.
+var virtualTrees = []string{"/dev", "/proc", "/sys"}
+
+// checkDeletePath decides whether path may be deleted while browsing root.
+// Looking for dirty git repositories below path can take a while on a large
+// tree, it stops when ctx is cancelled.
+func checkDeletePath(ctx context.Context, path, root string) deleteCheck {
+	path = filepath.Clean(path)
+	if refused := deleteRefusal(path, root); refused != "" {
+		return deleteCheck{Refused: refused}
+	}
+
+	var check deleteCheck
+	for _, tree := range systemTrees {
+		if isPathWithin(path, tree) {
+			check.Warnings = append(check.Warnings, "it is inside the system location "+tree)
+			break
+		}
+	}
+	if dirty, ok := hasUncommittedChanges(ctx, path); ok && dirty {
+		check.Warnings = append(check.Warnings, "it is a git repository with uncommitted changes")
+		return check
+	}
+	nested, complete := nestedDirtyRepositories(ctx, path)
+	if len(nested) == 1 {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("it holds the git repository %s with uncommitted changes", displayPath(nested[0])))
+	} else if len(nested) > 1 {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("it holds %d git repositories with uncommitted changes, such as %s", len(nested), displayPath(nested[0])))
+	}
+	if !complete {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("it has more than %d folders, nested git repositories were not fully checked", maxGuardWalkDirs))
+	}
+	return check
+}
+
+// deleteRefusal is the quick part of checkDeletePath: why path must never be
+// deleted while browsing root, or "".
+func deleteRefusal(path, root string) string {
+	path = filepath.Clean(path)
+	root = filepath.Clean(root)
+
+	if path == root {
+		return "it is the directory being analyzed"
+	}
+	if isPathWithin(root, path) {
+		return "it contains the directory being analyzed"
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		home = filepath.Clean(home)
+		if path == home {
+			return "it is your home directory"
+		}
+		if isPathWithin(home, path) {
+			return "it contains your home directory"
+		}
+	}
+	for _, protected := range protectedPaths {
+		if path == protected {
+			return "it is a system location"
+		}
+	}
+	for _, tree := range virtualTrees {
+		if isPathWithin(path, tree) {
+			return "it is part of a kernel file system"
+		}
+	}
+	if isMountPoint(path) {
+		return "it is a mount point"
+	}
+	// The target itself may be a symlink, removing it leaves the link target alone
+	if parent, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil && !isPathWithin(parent, resolvedRoot) && parent != resolvedRoot {
+			return fmt.Sprintf("it resolves to %s, outside the directory being analyzed", displayPath(filepath.Join(parent, filepath.Base(path))))
+		}
+	}
+	return ""
+}
+
+// isPathWithin reports whether path lies strictly below dir.
+func isPathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isMountPoint compares the device of path with that of its parent.
+func isMountPoint(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	parentInfo, err := os.Lstat(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	parentStat, parentOK := parentInfo.Sys().(*syscall.Stat_t)
+	return ok && parentOK && uint64(stat.Dev) != uint64(parentStat.Dev)
+}
+
+// hasUncommittedChanges reports whether path is the top of a git work tree with
+// uncommitted or untracked files. ok is false when path is not a repository.
+func hasUncommittedChanges(ctx context.Context, path string) (dirty, ok bool) {
+	if _, err := os.Lstat(filepath.Join(path, ".git")); err != nil {
+		return false, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCheckTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "git", "-C", path, "status", "--porcelain").Output()
+	if err != nil {
+		// A repository git cannot read is treated as dirty, to be safe
+		return true, true
+	}
+	return len(bytes.TrimSpace(output)) > 0, true
+}
+
+// nestedDirtyRepositories finds repositories with uncommitted changes below
+// path, such as the projects in ~/src. Only the first maxGuardWalkDirs
+// directories are searched; complete is false when the walk stopped early.
+func nestedDirtyRepositories(ctx context.Context, path string) (dirty []string, complete bool) {
+	complete = true
+	visited := 0
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			complete = false
+			return filepath.SkipAll
+		}
+		if err != nil || !d.IsDir() || p == path {
+			return nil
+		}
+		if visited++; visited > maxGuardWalkDirs {
+			complete = false
+			return filepath.SkipAll
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if isDirty, ok := hasUncommittedChanges(ctx, p); ok && isDirty {
+			dirty = append(dirty, p)
+		}
+		return nil
+	})
+	return dirty, complete
+}
+
+// deleteConfirmThreshold is the size from which deletion needs the name typed,
+// from MO_ANALYZE_CONFIRM_SIZE (e.g. "500M", "20G") or defaultConfirmSize.
+func deleteConfirmThreshold() int64 {
+	if size, ok := parseByteSize(os.Getenv("MO_ANALYZE_CONFIRM_SIZE")); ok {
+		return size
+	}
+	return defaultConfirmSize
+}
+
+// parseByteSize reads a byte count with an optional K, M, G or T suffix (powers of 1024).
+func parseByteSize(value string) (int64, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+	if value == "" {
+		return 0, false
+	}
+	multiplier := int64(1)
+	switch value[len(value)-1] {
+	case 'K':
+		multiplier = 1 << 10
+	case 'M':
+		multiplier = 1 << 20
+	case 'G':
+		multiplier = 1 << 30
+	case 'T':
+		multiplier = 1 << 40
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 {
+		return 0, false
+	}
+	return int64(number * float64(multiplier)), true
+}
+
+// deleteGuardMsg carries the checkDeletePath verdicts of the confirmed targets.
+type deleteGuardMsg struct {
+	targets []dirEntry
+	checks  []deleteCheck
+	err     error
+}
+
+// guardDelete runs the confirmed targets through checkDeletePath off the UI
+// goroutine, as looking for nested repositories walks the targets.
+func (m model) guardDelete() (tea.Model, tea.Cmd) {
+	targets := m.deleteBatch
+	if len(targets) == 0 && m.deleteTarget != nil {
+		targets = []dirEntry{*m.deleteTarget}
+	}
+	if len(targets) == 0 {
+		m.deleteConfirm = false
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.deleteGuardCancel = cancel
+	root := m.path
+	check := func() tea.Msg {
+		checks := make([]deleteCheck, len(targets))
+		for i, target := range targets {
+			checks[i] = checkDeletePath(ctx, target.Path, root)
+			if checks[i].Refused != "" {
+				break
+			}
+		}
+		return deleteGuardMsg{targets: targets, checks: checks, err: ctx.Err()}
+	}
+	return m, tea.Batch(check, tickCmd())
+}
+
+// handleDeleteGuard acts on the verdicts. Refused targets cancel the
+// deletion; warnings ask for the name to be typed.
+func (m model) handleDeleteGuard(msg deleteGuardMsg) (tea.Model, tea.Cmd) {
+	if m.deleteGuardCancel == nil || msg.err != nil {
+		return m, nil
+	}
+	m.deleteGuardCancel()
+	m.deleteGuardCancel = nil
+
+	targets := msg.targets
+	var total int64
+	for _, target := range targets {
+		total += target.Size
+	}
+
+	var warnings []string
+	for i, target := range targets {
+		check := msg.checks[i]
+		if check.Refused != "" {
+			m.deleteNotice = fmt.Sprintf("Refusing to delete %s: %s", displayPath(target.Path), check.Refused)
+			m.deleteConfirm = false
+			m.deleteTarget = nil
+			m.deleteBatch = nil
+			return m, nil
+		}
+		for _, warning := range check.Warnings {
+			if len(targets) > 1 {
+				warning = target.Name + ": " + warning
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	if threshold := deleteConfirmThreshold(); total > 0 && total >= threshold {
+		subject := "it is"
+		if len(targets) > 1 {
+			subject = "together they are"
+		}
+		warnings = append(warnings, fmt.Sprintf("%s larger than %s", subject, humanizeBytes(threshold)))
+	}
+	if len(warnings) == 0 {
+		return m.startDelete()
+	}
+	m.deleteWarnings = warnings
+	m.deleteTyped = &textInput{Prompt: fmt.Sprintf("Type %q to delete:", m.deleteConfirmWord())}
+	return m, nil
+}
+
+// updateDeleteGuardKey lets esc abandon the deletion while the targets are checked.
+func (m model) updateDeleteGuardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.deleteGuardCancel()
+		return m, tea.Quit
+	case "esc":
+		m.deleteGuardCancel()
+		m.deleteGuardCancel = nil
+		m.deleteConfirm = false
+		m.deleteTarget = nil
+		m.deleteBatch = nil
+		m.status = "Cancelled"
+	}
+	return m, nil
+}
+
+// deleteConfirmWord is what the user types: the entry name, or "delete" for a batch.
+func (m model) deleteConfirmWord() string {
+	if len(m.deleteBatch) > 0 || m.deleteTarget == nil {
+		return "delete"
+	}
+	return strings.TrimSuffix(m.deleteTarget.Name, " →")
+}
+
+func (m model) updateDeleteTyped(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.deleteTyped.handleKey(msg)
+	if !submitted && !cancelled {
+		return m, nil
+	}
+	typed := strings.TrimSpace(m.deleteTyped.Value)
+	m.deleteTyped = nil
+	m.deleteWarnings = nil
+	if submitted && typed == m.deleteConfirmWord() {
+		return m.startDelete()
+	}
+	if submitted {
+		m.deleteNotice = "Confirmation did not match, nothing deleted"
+	}
+	m.deleteConfirm = false
+	m.deleteTarget = nil
+	m.deleteBatch = nil
+	return m, nil
+}
+
+func (m model) startDelete() (tea.Model, tea.Cmd) {
+	targets := m.deleteBatch
+	if len(targets) == 0 && m.deleteTarget != nil {
+		targets = []dirEntry{*m.deleteTarget}
+	}
+	m.deleteConfirm = false
+	m.deleteTarget = nil
+	m.deleteBatch = nil
+	if len(targets) == 0 {
+		return m, nil
+	}
+
+	paths := make([]string, 0, len(targets))
+	for _, entry := range targets {
+		paths = append(paths, entry.Path)
+	}
+	if len(paths) == 1 {
+		m.status = fmt.Sprintf("Deleting %s...", targets[0].Name)
+	} else {
+		m.status = fmt.Sprintf("Deleting %d items...", len(paths))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.deleting = true
+	m.deleteCancel = cancel
+	m.deleteProgress = &deleteProgress{}
+	return m, tea.Batch(deletePathsCmd(ctx, paths, m.deleteProgress), tickCmd())
+}
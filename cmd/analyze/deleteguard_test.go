@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+		ok    bool
+	}{
+		{"1024", 1024, true},
+		{"500M", 500 << 20, true},
+		{"500MB", 500 << 20, true},
+		{"500MiB", 500 << 20, true},
+		{"20g", 20 << 30, true},
+		{" 1.5G ", 3 << 29, true},
+		{"2T", 2 << 40, true},
+		{"64K", 64 << 10, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"G", 0, false},
+		{"-1G", 0, false},
+		{"ten", 0, false},
+		{"10X", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseByteSize(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCheckDeletePath(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	home := filepath.Join(base, "home")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{root, home, outside, filepath.Join(root, "plain")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOME", home)
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		root     string
+		refused  string
+		warnings []string
+	}{
+		{"analyzed directory", root, root, "it is the directory being analyzed", nil},
+		{"parent of analyzed directory", base, root, "it contains the directory being analyzed", nil},
+		{"home", home, root, "it is your home directory", nil},
+		{"system root", "/usr", root, "it is a system location", nil},
+		{"kernel file system", "/proc/1", "/", "it is part of a kernel file system", nil},
+		{"through a symlinked parent", filepath.Join(root, "link", "file"), root,
+			"it resolves to " + displayPath(filepath.Join(outside, "file")) + ", outside the directory being analyzed", nil},
+		{"symlink itself", filepath.Join(root, "link"), root, "", nil},
+		{"plain directory", filepath.Join(root, "plain"), root, "", nil},
+		{"inside a system tree", "/usr/share/mole-test-missing", "/", "", []string{"it is inside the system location /usr"}},
+	}
+	for _, tt := range tests {
+		check := checkDeletePath(context.Background(), tt.path, tt.root)
+		if check.Refused != tt.refused || !slices.Equal(check.Warnings, tt.warnings) {
+			t.Errorf("%s: checkDeletePath(%q, %q) = %+v, want refused %q, warnings %q",
+				tt.name, tt.path, tt.root, check, tt.refused, tt.warnings)
+		}
+	}
+}
+
+func TestCheckDeletePathDirtyRepositories(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	repo := filepath.Join(root, "projects", "app")
+	clean := filepath.Join(root, "projects", "clean")
+	for _, dir := range []string{repo, clean} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+			t.Fatalf("git init: %v: %s", err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		warnings []string
+	}{
+		{repo, []string{"it is a git repository with uncommitted changes"}},
+		{clean, nil},
+		{filepath.Join(root, "projects"), []string{"it holds the git repository " + displayPath(repo) + " with uncommitted changes"}},
+	}
+	for _, tt := range tests {
+		check := checkDeletePath(context.Background(), tt.path, root)
+		if check.Refused != "" || !slices.Equal(check.Warnings, tt.warnings) {
+			t.Errorf("checkDeletePath(%q) = %+v, want warnings %q", tt.path, check, tt.warnings)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, complete := nestedDirtyRepositories(ctx, root); complete {
+		t.Error("nestedDirtyRepositories reported a cancelled search as complete")
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// entryDetails is the metadata shown in the details pane for one path.
+type entryDetails struct {
+	Path       string
+	Mode       fs.FileMode
+	Owner      string
+	Group      string
+	ModTime    time.Time
+	AccessTime time.Time
+	ChangeTime time.Time
+	LinkTarget string
+	Apparent   int64 // Sum of file sizes
+	OnDisk     int64 // Allocated blocks, smaller for sparse or compressed files
+	Files      int64
+	Dirs       int64
+	Largest    []fileEntry // Largest descendant files, biggest first
+	Counting   bool        // Stat done, directory walk still running
+	Err        error
+}
+
+type detailsMsg struct {
+	details *entryDetails
+}
+
+// detailsRequestMsg fires shortly after the selection moves, so holding an
+// arrow key does not start a walk for every entry passed over.
+type detailsRequestMsg struct {
+	path string
+}
+
+// detailsTarget is the path the details pane describes.
+func (m model) detailsTarget() (string, bool) {
+	if m.showLargeFiles {
+		if m.largeSelected < len(m.largeFiles) {
+			return m.largeFiles[m.largeSelected].Path, true
+		}
+		return "", false
+	}
+	entry, ok := m.selectedEntry()
+	return entry.Path, ok && entry.Path != ""
+}
+
+// requestDetails schedules loading details for the selection if they are missing.
+func (m model) requestDetails() tea.Cmd {
+	if !m.showDetails || m.scanning || m.inArchive() {
+		return nil
+	}
+	path, ok := m.detailsTarget()
+	if !ok || m.details[path] != nil || m.detailsLoading[path] {
+		return nil
+	}
+	return tea.Tick(detailsDebounce, func(time.Time) tea.Msg {
+		return detailsRequestMsg{path: path}
+	})
+}
+
+// loadDetails stats path right away, then walks it for counts and the largest files.
+func (m *model) loadDetails(path string) tea.Cmd {
+	if m.details == nil {
+		m.details = make(map[string]*entryDetails)
+	}
+	if m.detailsLoading == nil {
+		m.detailsLoading = make(map[string]bool)
+	}
+	if m.detailsCancel != nil {
+		// Only the selection is worth walking; the previous one is fetched again when revisited
+		m.detailsCancel()
+		delete(m.detailsLoading, m.detailsWalking)
+		if prev := m.details[m.detailsWalking]; prev != nil && prev.Counting {
+			delete(m.details, m.detailsWalking)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.detailsWalking = path
+	m.detailsCancel = cancel
+	m.detailsLoading[path] = true
+	return tea.Sequence(
+		func() tea.Msg { return detailsMsg{details: statDetails(path)} },
+		func() tea.Msg {
+			details := walkDetails(ctx, path)
+			if details == nil {
+				return nil
+			}
+			return detailsMsg{details: details}
+		},
+	)
+}
+
+func statDetails(path string) *entryDetails {
+	details := &entryDetails{Path: path}
+	info, err := os.Lstat(path)
+	if err != nil {
+		details.Err = err
+		return details
+	}
+	details.Mode = info.Mode()
+	details.ModTime = info.ModTime()
+	details.AccessTime = getLastAccessTimeFromInfo(info)
+	details.ChangeTime = getChangeTimeFromInfo(info)
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		details.Owner = lookupUserName(stat.Uid)
+		details.Group = lookupGroupName(stat.Gid)
+		details.OnDisk = stat.Blocks * 512
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		details.LinkTarget, _ = os.Readlink(path)
+	}
+	if info.Mode().IsRegular() {
+		details.Apparent = info.Size()
+	}
+	details.Counting = info.IsDir()
+	return details
+}
+
+// walkDetails counts everything below path. It returns nil when ctx is
+// cancelled before the walk completes.
+func walkDetails(ctx context.Context, path string) *entryDetails {
+	details := statDetails(path)
+	if !details.Counting {
+		return details
+	}
+	details.Counting = false
+	details.OnDisk = 0
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			details.OnDisk += stat.Blocks * 512
+		}
+		if p == path {
+			return nil
+		}
+		if d.IsDir() {
+			details.Dirs++
+			return nil
+		}
+		details.Files++
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		details.Apparent += info.Size()
+		if len(details.Largest) < maxDetailsFiles || info.Size() > details.Largest[len(details.Largest)-1].Size {
+			// Insert in place, the list is short and already sorted
+			at := sort.Search(len(details.Largest), func(i int) bool { return details.Largest[i].Size < info.Size() })
+			details.Largest = slices.Insert(details.Largest, at, fileEntry{Name: d.Name(), Path: p, Size: info.Size()})
+			if len(details.Largest) > maxDetailsFiles {
+				details.Largest = details.Largest[:maxDetailsFiles]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+	return details
+}
+
+func lookupUserName(uid uint32) string {
+	id := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(id); err == nil {
+		return u.Username
+	}
+	return id
+}
+
+func lookupGroupName(gid uint32) string {
+	id := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(id); err == nil {
+		return g.Name
+	}
+	return id
+}
+
+// detailsPaneVisible reports whether the current view is a plain list that can
+// share the screen with the details pane.
+func (m model) detailsPaneVisible() bool {
+	if !m.showDetails || m.inOverviewMode() || m.inArchive() {
+		return false
+	}
+	if len(m.deleteFailures) > 0 || m.showSweep || m.showTypes || m.showAges || m.showTopDirs || m.showOwners || m.showErrors || m.showGit || m.showPackages || m.showVersions || m.showTree {
+		return false
+	}
+	return m.showLargeFiles || !m.showTreemap
+}
+
+// withDetailsPane puts the details pane to the right of list, or below it when
+// the terminal is too narrow for both.
+func (m model) withDetailsPane(list string) string {
+	list = strings.TrimSuffix(list, "\n")
+	paneWidth := detailsPaneWidth
+	sideBySide := m.width >= lipgloss.Width(list)+paneWidth+4
+	if !sideBySide && m.width > 0 {
+		paneWidth = m.width - 4
+	}
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("8")).
+		Padding(0, 1).
+		Width(paneWidth).
+		Render(m.renderDetails(paneWidth - 2))
+	if sideBySide {
+		return lipgloss.JoinHorizontal(lipgloss.Top, list, "  ", pane) + "\n"
+	}
+	return list + "\n" + pane + "\n"
+}
+
+func (m model) renderDetails(width int) string {
+	path, ok := m.detailsTarget()
+	if !ok {
+		return colorGray + "Nothing selected" + colorReset
+	}
+	details := m.details[path]
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s\n", colorPurple, filepath.Base(path), colorReset)
+	fmt.Fprintf(&b, "%s%s%s\n", colorGray, wrapPath(path, width), colorReset)
+	if details == nil {
+		fmt.Fprintf(&b, "\n%sLoading...%s", colorGray, colorReset)
+		return b.String()
+	}
+	if details.Err != nil {
+		fmt.Fprintf(&b, "\n%s%v%s", colorRed, details.Err, colorReset)
+		return b.String()
+	}
+
+	row := func(label, value string) {
+		fmt.Fprintf(&b, "%s%-9s%s %s\n", colorGray, label, colorReset, value)
+	}
+	b.WriteString("\n")
+	switch {
+	case details.LinkTarget != "":
+		row("Type", "Symlink")
+		row("Target", truncateMiddle(details.LinkTarget, width-10))
+	case details.Mode.IsDir():
+		row("Type", "Directory")
+	default:
+		row("Type", "File")
+	}
+	row("Owner", details.Owner+":"+details.Group)
+	row("Mode", fmt.Sprintf("%s (%04o)", details.Mode.String(), details.Mode.Perm()))
+	row("Modified", formatDetailsTime(details.ModTime))
+	row("Accessed", formatDetailsTime(details.AccessTime))
+	row("Changed", formatDetailsTime(details.ChangeTime))
+	b.WriteString("\n")
+	if details.Counting {
+		row("Size", colorGray+"counting..."+colorReset)
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	row("Size", humanizeBytes(details.Apparent))
+	row("On disk", humanizeBytes(details.OnDisk))
+	if details.Mode.IsDir() {
+		row("Contains", fmt.Sprintf("%s files, %s dirs", formatNumber(details.Files), formatNumber(details.Dirs)))
+		if len(details.Largest) > 0 {
+			fmt.Fprintf(&b, "\n%sLargest inside%s\n", colorGray, colorReset)
+			for _, file := range details.Largest {
+				rel := strings.TrimPrefix(file.Path, path+string(filepath.Separator))
+				fmt.Fprintf(&b, "%s %s%10s%s\n",
+					padName(truncateMiddle(rel, width-12), width-12), colorGray, humanizeBytes(file.Size), colorReset)
+			}
+		}
+	}
+	m.renderCompressDetails(&b, path, width)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatDetailsTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s %s(%s)%s", t.Format("2006-01-02 15:04"), colorGray, formatAge(t), colorReset)
+}
+
+// wrapPath breaks path into lines of at most width columns.
+func wrapPath(path string, width int) string {
+	if width <= 0 {
+		return path
+	}
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, r := range path {
+		w := runeWidth(r)
+		if lineWidth+w > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		line.WriteRune(r)
+		lineWidth += w
+	}
+	return strings.Join(append(lines, line.String()), "\n")
+}
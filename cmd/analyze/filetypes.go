@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	categoryVideo     = "Video"
+	categoryImages    = "Images"
+	categoryAudio     = "Audio"
+	categoryArchives  = "Archives"
+	categoryDiskImage = "Disk images & VMs"
+	categoryDocuments = "Documents"
+	categoryCode      = "Code"
+	categoryBinaries  = "Binaries"
+	categoryOther     = "Other"
+
+	// foldedExtension labels bytes of folded directories (node_modules, caches...)
+	// that are measured with du and never listed file by file.
+	foldedExtension = "(folded dirs)"
+)
+
+var categoryIcons = map[string]string{
+	categoryVideo:     "🎬",
+	categoryImages:    "🌄",
+	categoryAudio:     "🎵",
+	categoryArchives:  "📦",
+	categoryDiskImage: "💿",
+	categoryDocuments: "📄",
+	categoryCode:      "📝",
+	categoryBinaries:  "🔧",
+	categoryOther:     "📁",
+}
+
+var extensionCategories = map[string]string{
+	// Video
+	".mp4": categoryVideo, ".mov": categoryVideo, ".mkv": categoryVideo, ".avi": categoryVideo,
+	".wmv": categoryVideo, ".flv": categoryVideo, ".webm": categoryVideo, ".m4v": categoryVideo,
+	".mpg": categoryVideo, ".mpeg": categoryVideo, ".3gp": categoryVideo, ".vob": categoryVideo,
+	".mts": categoryVideo, ".m2ts": categoryVideo, ".prproj": categoryVideo, ".fcpbundle": categoryVideo,
+
+	// Images
+	".jpg": categoryImages, ".jpeg": categoryImages, ".png": categoryImages, ".gif": categoryImages,
+	".heic": categoryImages, ".heif": categoryImages, ".webp": categoryImages, ".tif": categoryImages,
+	".tiff": categoryImages, ".bmp": categoryImages, ".raw": categoryImages, ".cr2": categoryImages,
+	".cr3": categoryImages, ".nef": categoryImages, ".arw": categoryImages, ".dng": categoryImages,
+	".psd": categoryImages, ".svg": categoryImages, ".ico": categoryImages, ".icns": categoryImages,
+	".sketch": categoryImages, ".fig": categoryImages, ".xcf": categoryImages,
+
+	// Audio
+	".mp3": categoryAudio, ".wav": categoryAudio, ".flac": categoryAudio, ".aac": categoryAudio,
+	".m4a": categoryAudio, ".ogg": categoryAudio, ".opus": categoryAudio, ".aiff": categoryAudio,
+	".aif": categoryAudio, ".wma": categoryAudio, ".alac": categoryAudio, ".logicx": categoryAudio,
+
+	// Archives
+	".zip": categoryArchives, ".tar": categoryArchives, ".gz": categoryArchives, ".tgz": categoryArchives,
+	".bz2": categoryArchives, ".xz": categoryArchives, ".zst": categoryArchives, ".7z": categoryArchives,
+	".rar": categoryArchives, ".jar": categoryArchives, ".war": categoryArchives, ".lz4": categoryArchives,
+	".pkg": categoryArchives, ".deb": categoryArchives, ".rpm": categoryArchives, ".xip": categoryArchives,
+	".apk": categoryArchives, ".ipa": categoryArchives,
+
+	// Disk images and virtual machines
+	".dmg": categoryDiskImage, ".iso": categoryDiskImage, ".img": categoryDiskImage, ".sparseimage": categoryDiskImage,
+	".sparsebundle": categoryDiskImage, ".vmdk": categoryDiskImage, ".vdi": categoryDiskImage, ".vhd": categoryDiskImage,
+	".vhdx": categoryDiskImage, ".qcow2": categoryDiskImage, ".hdd": categoryDiskImage, ".ova": categoryDiskImage,
+	".ovf": categoryDiskImage, ".utm": categoryDiskImage, ".pvm": categoryDiskImage, ".raw-disk": categoryDiskImage,
+
+	// Documents
+	".pdf": categoryDocuments, ".doc": categoryDocuments, ".docx": categoryDocuments, ".xls": categoryDocuments,
+	".xlsx": categoryDocuments, ".ppt": categoryDocuments, ".pptx": categoryDocuments, ".pages": categoryDocuments,
+	".numbers": categoryDocuments, ".key": categoryDocuments, ".odt": categoryDocuments, ".ods": categoryDocuments,
+	".odp": categoryDocuments, ".rtf": categoryDocuments, ".txt": categoryDocuments, ".md": categoryDocuments,
+	".epub": categoryDocuments, ".mobi": categoryDocuments, ".csv": categoryDocuments, ".tex": categoryDocuments,
+
+	// Code and text sources
+	".go": categoryCode, ".js": categoryCode, ".ts": categoryCode, ".tsx": categoryCode, ".jsx": categoryCode,
+	".json": categoryCode, ".yml": categoryCode, ".yaml": categoryCode, ".xml": categoryCode,
+	".html": categoryCode, ".css": categoryCode, ".scss": categoryCode, ".sass": categoryCode,
+	".less": categoryCode, ".py": categoryCode, ".rb": categoryCode, ".java": categoryCode,
+	".kt": categoryCode, ".rs": categoryCode, ".swift": categoryCode, ".m": categoryCode,
+	".mm": categoryCode, ".c": categoryCode, ".cpp": categoryCode, ".h": categoryCode,
+	".hpp": categoryCode, ".cs": categoryCode, ".sql": categoryCode, ".sh": categoryCode,
+	".mjs": categoryCode, ".cjs": categoryCode, ".vue": categoryCode, ".svelte": categoryCode,
+	".dart": categoryCode, ".php": categoryCode, ".lua": categoryCode, ".toml": categoryCode,
+	".lock": categoryCode, ".gradle": categoryCode, ".map": categoryCode, ".ipynb": categoryCode,
+
+	// Compiled binaries and libraries
+	".dylib": categoryBinaries, ".so": categoryBinaries, ".a": categoryBinaries, ".o": categoryBinaries,
+	".dll": categoryBinaries, ".exe": categoryBinaries, ".bin": categoryBinaries, ".class": categoryBinaries,
+	".wasm": categoryBinaries, ".node": categoryBinaries, ".pyc": categoryBinaries, ".rlib": categoryBinaries,
+	".car": categoryBinaries,
+}
+
+// fileCategory classifies a file by extension; extensionless executables are binaries.
+func fileCategory(ext string, info fs.FileInfo) string {
+	if category, ok := extensionCategories[ext]; ok {
+		return category
+	}
+	if ext == "" && info != nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+		return categoryBinaries
+	}
+	return categoryOther
+}
+
+func (m model) currentCategory() (categoryStat, bool) {
+	if m.insights == nil || m.typeDrill == "" {
+		return categoryStat{}, false
+	}
+	for _, stat := range m.insights.Categories {
+		if stat.Name == m.typeDrill {
+			return stat, true
+		}
+	}
+	return categoryStat{}, false
+}
+
+func (m model) updateTypesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	category, drilled := m.currentCategory()
+	var categories []categoryStat
+	if m.insights != nil {
+		categories = m.insights.Categories
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.typeDrill = ""
+		return m.refreshCurrentPath()
+	case "esc", "b", "left", "h", "t":
+		if drilled && msg.String() != "t" {
+			m.typeDrill = ""
+			return m, nil
+		}
+		m.showTypes = false
+		m.typeDrill = ""
+		return m, nil
+	case "up", "k":
+		if drilled {
+			m.typeFileCursor.up()
+		} else {
+			m.typeCursor.up()
+		}
+	case "down", "j":
+		if drilled {
+			m.typeFileCursor.down(len(category.Largest), headedViewport(m.height))
+		} else {
+			m.typeCursor.down(len(categories), calculateViewport(m.height, true))
+		}
+	case "enter", "right", "l":
+		if !drilled && m.typeCursor.Selected < len(categories) {
+			m.typeDrill = categories[m.typeCursor.Selected].Name
+			m.typeFileCursor = listCursor{}
+		}
+	case "o", "f", "F":
+		if drilled && m.typeFileCursor.Selected < len(category.Largest) {
+			file := category.Largest[m.typeFileCursor.Selected]
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(file.Path)
+		}
+	case "delete", "backspace":
+		if drilled && m.typeFileCursor.Selected < len(category.Largest) {
+			file := category.Largest[m.typeFileCursor.Selected]
+			m.deleteConfirm = true
+			m.deleteTarget = &dirEntry{Name: file.Name, Path: file.Path, Size: file.Size}
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderTypes(b *strings.Builder) {
+	if m.insights == nil || len(m.insights.Categories) == 0 {
+		fmt.Fprintln(b, "  No file type data for this scan, press R to rescan")
+		return
+	}
+	if category, ok := m.currentCategory(); ok {
+		m.renderCategoryFiles(b, category)
+		return
+	}
+
+	var total, maxSize int64 = 0, 1
+	for _, stat := range m.insights.Categories {
+		total += stat.Size
+		if stat.Size > maxSize {
+			maxSize = stat.Size
+		}
+	}
+	viewport := calculateViewport(m.height, true)
+	start, end := m.typeCursor.window(len(m.insights.Categories), viewport)
+	for idx := start; idx < end; idx++ {
+		stat := m.insights.Categories[idx]
+		percent := 0.0
+		if total > 0 {
+			percent = float64(stat.Size) / float64(total) * 100
+		}
+		bar := coloredProgressBar(stat.Size, maxSize, percent)
+		name := padName(stat.Name, 20)
+		icon := categoryIcons[stat.Name]
+
+		entryPrefix := "   "
+		nameSegment := fmt.Sprintf("%s %s", icon, name)
+		numColor, percentColor, sizeColor := "", "", colorGray
+		if idx == m.typeCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameSegment = fmt.Sprintf("%s%s %s%s", colorCyan, icon, name, colorReset)
+			numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  %s %s%10s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameSegment, sizeColor, humanizeBytes(stat.Size), colorReset,
+			colorGray, topExtensionsLabel(stat), colorReset)
+	}
+}
+
+func (m model) renderCategoryFiles(b *strings.Builder, category categoryStat) {
+	fmt.Fprintf(b, "%s%s %s: %s in %s files%s\n\n", colorGray, categoryIcons[category.Name], category.Name,
+		humanizeBytes(category.Size), formatNumber(category.Count), colorReset)
+	m.renderFileList(b, category.Largest, m.typeFileCursor)
+}
+
+// renderFileList draws a drill-down list of files below a one-line header.
+func (m model) renderFileList(b *strings.Builder, files []fileEntry, cursor listCursor) {
+	if len(files) == 0 {
+		fmt.Fprintln(b, "  No individual files recorded")
+		return
+	}
+	maxSize := files[0].Size
+	viewport := headedViewport(m.height)
+	start, end := cursor.window(len(files), viewport)
+	for idx := start; idx < end; idx++ {
+		file := files[idx]
+		shortPath := padName(truncateMiddle(displayPath(file.Path), 35), 35)
+		entryPrefix := "   "
+		nameColor, sizeColor, numColor := "", colorGray, ""
+		if idx == cursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, sizeColor, numColor = colorCyan, colorCyan, colorCyan
+		}
+		bar := coloredProgressBar(file.Size, maxSize, 0)
+		fmt.Fprintf(b, "%s%s%2d.%s %s  |  📄 %s%s%s  %s%10s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, nameColor, shortPath, colorReset, sizeColor, humanizeBytes(file.Size), colorReset)
+	}
+}
+
+// topExtensionsLabel lists the three biggest extensions, e.g. "mp4 8.1 GB · mov 3.2 GB".
+func topExtensionsLabel(stat categoryStat) string {
+	type extSize struct {
+		ext  string
+		size int64
+	}
+	exts := make([]extSize, 0, len(stat.Extensions))
+	for ext, size := range stat.Extensions {
+		exts = append(exts, extSize{ext, size})
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		return exts[i].size > exts[j].size
+	})
+	var parts []string
+	for i, e := range exts {
+		if i == 3 {
+			break
+		}
+		label := strings.TrimPrefix(e.ext, ".")
+		if label == "" {
+			label = "(none)"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", label, humanizeBytes(e.size)))
+	}
+	return fmt.Sprintf("%s files · %s", formatNumber(stat.Count), strings.Join(parts, " · "))
+}
@@ -228,3 +228,20 @@ func formatUnusedTime(lastAccess time.Time) string {
 
 	return ""
 }
+
+// formatAge formats how long ago t was, e.g. "5d ago", "8mo ago".
+func formatAge(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 60:
+		return fmt.Sprintf("%dw ago", days/7)
+	case days < 365:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dyr ago", days/365)
+	}
+}
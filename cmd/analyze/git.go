@@ -0,0 +1,399 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitReport explains where the bytes of a git work tree go.
+type gitReport struct {
+	Root      string // Top of the work tree
+	GitDir    string
+	Tracked   gitBucket
+	Untracked gitBucket
+	Ignored   gitBucket
+	Packs     int64 // objects/pack
+	Loose     int64 // Loose objects in objects/xx
+	LooseObjs int64
+	LFS       int64 // lfs/objects and friends
+	OtherGit  int64 // Everything else under the git dir
+	Blobs     []gitBlob
+	BlobErr   error // History could not be read, the size split is still valid
+}
+
+// gitBucket is one slice of the work tree with its largest paths.
+type gitBucket struct {
+	Size    int64
+	Count   int64
+	Largest []dirStat
+}
+
+// gitBlob is one blob reachable from any ref.
+type gitBlob struct {
+	Hash string
+	Path string
+	Size int64 // Uncompressed
+	Disk int64 // Stored size, after zlib and delta compression
+}
+
+type gitReportMsg struct {
+	root       string
+	report     *gitReport
+	unreadable *insightCollector
+	err        error
+}
+
+func gitCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "LC_ALL=C")
+	return cmd
+}
+
+// gitWorkTree returns the top of the work tree holding dir and its git dir.
+func gitWorkTree(ctx context.Context, dir string) (string, string, error) {
+	out, err := gitCommand(ctx, dir, "rev-parse", "--show-toplevel", "--absolute-git-dir").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", "", errors.New("not inside a git work tree")
+		}
+		return "", "", err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		return "", "", errors.New("not inside a git work tree")
+	}
+	return lines[0], lines[1], nil
+}
+
+func gitReportCmd(ctx context.Context, dir string, filesScanned, bytesScanned *int64, currentPath *string) tea.Cmd {
+	return func() tea.Msg {
+		unreadable := &insightCollector{}
+		report, err := analyzeGitRepo(ctx, dir, unreadable, filesScanned, bytesScanned, currentPath)
+		if ctx.Err() != nil {
+			// Killed git commands fail with their own errors, report the cancellation instead
+			return gitReportMsg{root: dir, err: ctx.Err()}
+		}
+		return gitReportMsg{root: dir, report: report, unreadable: unreadable, err: err}
+	}
+}
+
+// analyzeGitRepo splits dir into tracked, untracked and ignored bytes, breaks
+// down the git dir and finds the largest blobs in the history.
+func analyzeGitRepo(ctx context.Context, dir string, unreadable *insightCollector, filesScanned, bytesScanned *int64, currentPath *string) (*gitReport, error) {
+	root, gitDir, err := gitWorkTree(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &gitReport{Root: root, GitDir: gitDir}
+	measure := func(bucket *gitBucket, args ...string) error {
+		out, err := gitCommand(ctx, dir, append([]string{"ls-files", "-z"}, args...)...).Output()
+		if err != nil {
+			return fmt.Errorf("git ls-files: %w", err)
+		}
+		var last string
+		for _, name := range strings.Split(string(out), "\x00") {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if name == "" || name == last {
+				continue // Unmerged paths are listed once per stage
+			}
+			last = name
+			path := filepath.Join(dir, name)
+			if currentPath != nil {
+				*currentPath = path
+			}
+			var size int64
+			if strings.HasSuffix(name, "/") {
+				// Collapsed untracked or ignored directory
+				size, _ = getDirectoryLogicalSize(path, unreadable)
+			} else if info, err := os.Lstat(path); err == nil && info.Mode().IsRegular() {
+				size = getActualFileSize(path, info)
+			}
+			bucket.Size += size
+			bucket.Count++
+			bucket.Largest = appendTopDirs(bucket.Largest, dirStat{Path: filepath.Clean(path), Size: size}, dirBySize)
+			atomic.AddInt64(filesScanned, 1)
+			atomic.AddInt64(bytesScanned, size)
+		}
+		bucket.Largest = topDirs(bucket.Largest, dirBySize)
+		return nil
+	}
+	if err := measure(&report.Tracked, "--cached"); err != nil {
+		return nil, err
+	}
+	if err := measure(&report.Untracked, "--others", "--exclude-standard", "--directory"); err != nil {
+		return nil, err
+	}
+	if err := measure(&report.Ignored, "--others", "--ignored", "--exclude-standard", "--directory"); err != nil {
+		return nil, err
+	}
+
+	objects := filepath.Join(gitDir, "objects")
+	_ = filepath.WalkDir(gitDir, func(p string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size := getActualFileSize(p, info)
+		atomic.AddInt64(bytesScanned, size)
+		rel, _ := filepath.Rel(gitDir, p)
+		switch {
+		case isPathWithin(p, filepath.Join(objects, "pack")):
+			report.Packs += size
+		case isLooseObject(objects, p):
+			report.Loose += size
+			report.LooseObjs++
+		case strings.HasPrefix(rel, "lfs"+string(filepath.Separator)):
+			report.LFS += size
+		default:
+			report.OtherGit += size
+		}
+		return nil
+	})
+
+	report.Blobs, report.BlobErr = largestGitBlobs(ctx, root)
+	return report, nil
+}
+
+// isLooseObject matches objects/xx/yyyy..., the layout of unpacked objects.
+func isLooseObject(objects, path string) bool {
+	rel, err := filepath.Rel(objects, path)
+	if err != nil {
+		return false
+	}
+	dir, name := filepath.Split(rel)
+	dir = strings.TrimSuffix(dir, string(filepath.Separator))
+	if len(dir) != 2 || len(name) < 38 {
+		return false
+	}
+	_, err = strconv.ParseUint(dir, 16, 8)
+	return err == nil
+}
+
+// largestGitBlobs lists every blob reachable from any ref with the path it
+// was first seen at, and keeps the maxGitBlobs largest. The listing is read as
+// it streams, history of millions of objects never sits in memory at once.
+func largestGitBlobs(ctx context.Context, root string) ([]gitBlob, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	revList := gitCommand(ctx, root, "rev-list", "--objects", "--all")
+	catFile := gitCommand(ctx, root, "cat-file", "--batch-check=%(objecttype) %(objectname) %(objectsize) %(objectsize:disk) %(rest)")
+	pipe, err := revList.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	catFile.Stdin = pipe
+	out, err := catFile.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := revList.Start(); err != nil {
+		return nil, err
+	}
+	if err := catFile.Start(); err != nil {
+		cancel()
+		_ = revList.Wait()
+		return nil, err
+	}
+
+	var blobs []gitBlob
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 5)
+		if len(fields) < 4 || fields[0] != "blob" {
+			continue
+		}
+		blob := gitBlob{Hash: fields[1]}
+		blob.Size, _ = strconv.ParseInt(fields[2], 10, 64)
+		blob.Disk, _ = strconv.ParseInt(fields[3], 10, 64)
+		if len(fields) == 5 {
+			blob.Path = fields[4]
+		}
+		blobs = append(blobs, blob)
+		if len(blobs) >= maxGitBlobs*4 {
+			blobs = topGitBlobs(blobs)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// Stop both commands rather than wait for output nobody reads
+		cancel()
+		_ = catFile.Wait()
+		_ = revList.Wait()
+		return nil, err
+	}
+	if err := catFile.Wait(); err != nil {
+		// rev-list would block writing to a pipe nobody reads any more
+		cancel()
+		_ = revList.Wait()
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	if err := revList.Wait(); err != nil {
+		return nil, fmt.Errorf("git rev-list: %w", err)
+	}
+	return topGitBlobs(blobs), nil
+}
+
+func topGitBlobs(blobs []gitBlob) []gitBlob {
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Size > blobs[j].Size })
+	if len(blobs) > maxGitBlobs {
+		blobs = blobs[:maxGitBlobs]
+	}
+	return blobs
+}
+
+func (m model) startGitReport() (tea.Model, tea.Cmd) {
+	m.gitReport = nil
+	m.status = fmt.Sprintf("Reading git repository at %s...", displayPath(m.path))
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	if m.gitCancel != nil {
+		m.gitCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.gitCancel = cancel
+	return m, tea.Batch(gitReportCmd(ctx, m.path, m.filesScanned, m.bytesScanned, m.currentPath), tickCmd())
+}
+
+// updateGitLoadingKey lets the user stop reading a large repository; git
+// itself is killed rather than left running in the background.
+func (m model) updateGitLoadingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.gitCancel()
+		return m, tea.Quit
+	case "esc", "G", "b", "left", "h":
+		m.gitCancel()
+		m.gitCancel = nil
+		m.scanning = false
+		m.showGit = false
+		m.status = "Cancelled"
+	}
+	return m, nil
+}
+
+func (m model) updateGitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var blobs []gitBlob
+	if m.gitReport != nil {
+		blobs = m.gitReport.Blobs
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "G", "b", "left", "h":
+		m.showGit = false
+	case "r", "R":
+		return m.startGitReport()
+	case "up", "k":
+		m.gitCursor.up()
+	case "down", "j":
+		m.gitCursor.down(len(blobs), m.gitBlobViewport())
+	}
+	return m, nil
+}
+
+// gitBlobViewport is what is left for the blob list below the size split.
+func (m model) gitBlobViewport() int {
+	return max(headedViewport(m.height)-16, 3)
+}
+
+func (m model) renderGit(b *strings.Builder) {
+	report := m.gitReport
+	if report == nil {
+		fmt.Fprintln(b, "  No git data, press R to read the repository")
+		return
+	}
+	scope := ""
+	if m.path != report.Root {
+		scope = "  |  work tree split for " + displayPath(m.path) + " only"
+	}
+	fmt.Fprintf(b, "%s🌿 Git repository %s%s%s\n\n", colorGray, displayPath(report.Root), scope, colorReset)
+
+	gitTotal := report.Packs + report.Loose + report.LFS + report.OtherGit
+	total := report.Tracked.Size + report.Untracked.Size + report.Ignored.Size + gitTotal
+	largest := max(report.Tracked.Size, report.Untracked.Size, report.Ignored.Size, gitTotal)
+	row := func(icon, label string, size int64, note string) {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(size) / float64(total) * 100
+		}
+		fmt.Fprintf(b, "    %s %5.1f%%  |  %s %s %10s  %s%s%s\n",
+			coloredProgressBar(size, largest, percent), percent, icon, padName(label, 16),
+			humanizeBytes(size), colorGray, note, colorReset)
+	}
+	largestNote := func(bucket gitBucket) string {
+		var names []string
+		for _, dir := range bucket.Largest {
+			if len(names) == 3 || dir.Size == 0 {
+				break
+			}
+			names = append(names, fmt.Sprintf("%s %s", relativeToScan(m.path, dir.Path), humanizeBytes(dir.Size)))
+		}
+		return strings.Join(names, ", ")
+	}
+	row("📄", "Tracked", report.Tracked.Size, fmt.Sprintf("%s files", formatNumber(report.Tracked.Count)))
+	row("📝", "Untracked", report.Untracked.Size, largestNote(report.Untracked))
+	row("🙈", "Ignored", report.Ignored.Size, largestNote(report.Ignored))
+	row("📦", ".git", gitTotal, "")
+	gitRow := func(label string, size int64, note string) {
+		fmt.Fprintf(b, "    %s         %s %10s  %s\n", strings.Repeat(" ", barWidth), padName("  "+label, 19), humanizeBytes(size), note)
+	}
+	gitRow("Packs", report.Packs, "")
+	gitRow("Loose objects", report.Loose, fmt.Sprintf("%s objects", formatNumber(report.LooseObjs)))
+	gitRow("LFS", report.LFS, "")
+	gitRow("Other", report.OtherGit, "")
+	if report.LooseObjs >= gitLooseObjectsHint {
+		fmt.Fprintf(b, "\n%s⚠ %s loose objects. Run git -C %s gc to pack them%s\n", colorYellow,
+			formatNumber(report.LooseObjs), shellQuote(report.Root), colorReset)
+	}
+
+	fmt.Fprintf(b, "\n%sLargest blobs in history%s\n", colorGray, colorReset)
+	if report.BlobErr != nil {
+		fmt.Fprintf(b, "  Cannot read the history: %v\n", report.BlobErr)
+		return
+	}
+	if len(report.Blobs) == 0 {
+		fmt.Fprintln(b, "  No commits yet")
+		return
+	}
+	start, end := m.gitCursor.window(len(report.Blobs), m.gitBlobViewport())
+	for idx := start; idx < end; idx++ {
+		blob := report.Blobs[idx]
+		entryPrefix := "   "
+		nameColor, numColor, sizeColor := "", "", colorGray
+		if idx == m.gitCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, numColor, sizeColor = colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s%s%s %s%10s%s  %s%s on disk  %s%s\n",
+			entryPrefix, numColor, idx+1, colorReset,
+			nameColor, padName(truncateMiddle(blob.Path, 50), 50), colorReset,
+			sizeColor, humanizeBytes(blob.Size), colorReset,
+			colorGray, humanizeBytes(blob.Disk), blob.Hash[:min(10, len(blob.Hash))], colorReset)
+	}
+}
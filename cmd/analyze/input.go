@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textInput is a minimal single-line prompt for the few actions that need
+// typed input (paths, confirmation names).
+type textInput struct {
+	Prompt string
+	Value  string
+}
+
+// handleKey applies a key press; it reports whether the input was submitted
+// with enter or cancelled with esc.
+func (t *textInput) handleKey(msg tea.KeyMsg) (submitted, cancelled bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return true, false
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return false, true
+	case tea.KeyBackspace, tea.KeyDelete:
+		if runes := []rune(t.Value); len(runes) > 0 {
+			t.Value = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		t.Value = ""
+	case tea.KeySpace:
+		t.Value += " "
+	case tea.KeyRunes:
+		t.Value += string(msg.Runes)
+	}
+	return false, false
+}
+
+func (t textInput) view() string {
+	return fmt.Sprintf("%s%s%s %s%s▏%s  %sEnter confirm  |  ESC cancel%s\n",
+		colorCyan, t.Prompt, colorReset,
+		strings.TrimRight(t.Value, "\n"), colorCyan, colorReset,
+		colorGray, colorReset)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scanInsights holds aggregates gathered during a full scan, beyond the
+// per-entry sizes. It travels with scan results, the disk cache and history.
+type scanInsights struct {
+	Categories []categoryStat
+	Ages       []ageBucket // One per ageBucketLimits entry, newest first
+	TopDirs    []dirStat   // Directories at any depth, biggest total first
+	HeavyDirs  []dirStat   // Directories at any depth, biggest own size first
+	Users      []ownerStat // Bytes per owning user, biggest first
+	Groups     []ownerStat // Bytes per owning group, biggest first
+	Errors     []scanError // Unreadable paths, capped at maxScanErrors
+	ErrorCount int64       // All unreadable paths, including those not kept
+}
+
+type categoryStat struct {
+	Name       string
+	Size       int64
+	Count      int64
+	Extensions map[string]int64 // Bytes per extension, "" for none
+	Largest    []fileEntry      // Biggest files, for drill-down
+}
+
+// insightCollector is shared by all scan workers. Workers tally one directory
+// at a time and merge once, keeping lock traffic proportional to directories.
+type insightCollector struct {
+	mu         sync.Mutex
+	tally      *fileTally
+	errors     []scanError
+	errorCount int64
+}
+
+type fileTally struct {
+	categories map[string]*categoryStat
+	modified   []ageStat
+	accessed   []ageStat
+	topDirs    []dirStat
+	heavyDirs  []dirStat
+	users      map[uint32]*ownerStat
+	groups     map[uint32]*ownerStat
+	owners     ownerUsage // Subtree bytes per owner of the directory being tallied
+	now        time.Time
+}
+
+func newInsightCollector() *insightCollector {
+	return &insightCollector{tally: newFileTally()}
+}
+
+func newFileTally() *fileTally {
+	return &fileTally{
+		categories: make(map[string]*categoryStat),
+		modified:   make([]ageStat, len(ageBucketLimits)),
+		accessed:   make([]ageStat, len(ageBucketLimits)),
+		users:      make(map[uint32]*ownerStat),
+		groups:     make(map[uint32]*ownerStat),
+		owners:     newOwnerUsage(),
+		now:        time.Now(),
+	}
+}
+
+func (t *fileTally) category(name string) *categoryStat {
+	stat, ok := t.categories[name]
+	if !ok {
+		stat = &categoryStat{Name: name, Extensions: make(map[string]int64)}
+		t.categories[name] = stat
+	}
+	return stat
+}
+
+func (t *fileTally) addFile(path string, size int64, info fs.FileInfo) {
+	if t == nil {
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	stat := t.category(fileCategory(ext, info))
+	stat.Size += size
+	stat.Count++
+	stat.Extensions[ext] += size
+	file := fileEntry{Name: filepath.Base(path), Path: path, Size: size}
+	if size > 0 {
+		stat.Largest = appendLargest(stat.Largest, file)
+	}
+	if info != nil {
+		t.modified[ageBucketIndex(info.ModTime(), t.now)].add(file)
+		if accessed := getLastAccessTimeFromInfo(info); !accessed.IsZero() {
+			t.accessed[ageBucketIndex(accessed, t.now)].add(file)
+		}
+	}
+	if uid, gid, ok := fileOwner(info); ok {
+		t.addOwned(uid, gid, size, 1)
+	}
+}
+
+func (t *fileTally) addOwned(uid, gid uint32, size, count int64) {
+	user, group := ownerEntry(t.users, uid), ownerEntry(t.groups, gid)
+	user.Size += size
+	user.Count += count
+	group.Size += size
+	group.Count += count
+	t.owners.add(uid, gid, size)
+}
+
+// addDir records a fully scanned directory and the size of its largest subdirectory.
+func (t *fileTally) addDir(path string, size, largestChild int64) {
+	if t == nil || size <= 0 {
+		return
+	}
+	dir := dirStat{Path: path, Size: size, Own: size - largestChild}
+	t.topDirs = appendTopDirs(t.topDirs, dir, dirBySize)
+	t.heavyDirs = appendTopDirs(t.heavyDirs, dir, dirByOwn)
+	addOwnerDirs(t.users, path, t.owners.users)
+	addOwnerDirs(t.groups, path, t.owners.groups)
+}
+
+// addFolded accounts for directories measured with du, whose files are never listed.
+func (c *insightCollector) addFolded(path string, size int64) {
+	if c == nil || size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stat := c.tally.category(categoryOther)
+	stat.Size += size
+	stat.Extensions[foldedExtension] += size
+	// Subdirectories of folded dirs are unknown, so all of it counts as own size
+	dir := dirStat{Path: path, Size: size, Own: size, Folded: true}
+	c.tally.topDirs = appendTopDirs(c.tally.topDirs, dir, dirBySize)
+	c.tally.heavyDirs = appendTopDirs(c.tally.heavyDirs, dir, dirByOwn)
+	if uid, gid, ok := pathOwner(path); ok {
+		ownerEntry(c.tally.users, uid).Size += size
+		ownerEntry(c.tally.groups, gid).Size += size
+		addOwnerDirs(c.tally.users, path, map[uint32]int64{uid: size})
+		addOwnerDirs(c.tally.groups, path, map[uint32]int64{gid: size})
+	}
+}
+
+func (c *insightCollector) merge(t *fileTally) {
+	if c == nil || t == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, src := range t.categories {
+		dst := c.tally.category(name)
+		dst.Size += src.Size
+		dst.Count += src.Count
+		for ext, size := range src.Extensions {
+			dst.Extensions[ext] += size
+		}
+		for _, file := range src.Largest {
+			dst.Largest = appendLargest(dst.Largest, file)
+		}
+	}
+	for i := range t.modified {
+		c.tally.modified[i].merge(t.modified[i])
+		c.tally.accessed[i].merge(t.accessed[i])
+	}
+	for _, dir := range t.topDirs {
+		c.tally.topDirs = appendTopDirs(c.tally.topDirs, dir, dirBySize)
+	}
+	for _, dir := range t.heavyDirs {
+		c.tally.heavyDirs = appendTopDirs(c.tally.heavyDirs, dir, dirByOwn)
+	}
+	mergeOwners(c.tally.users, t.users)
+	mergeOwners(c.tally.groups, t.groups)
+}
+
+func (c *insightCollector) result() *scanInsights {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	insights := &scanInsights{}
+	for _, stat := range c.tally.categories {
+		stat.Largest = topLargest(stat.Largest)
+		insights.Categories = append(insights.Categories, *stat)
+	}
+	sort.Slice(insights.Categories, func(i, j int) bool {
+		return insights.Categories[i].Size > insights.Categories[j].Size
+	})
+	for i, limit := range ageBucketLimits {
+		modified, accessed := c.tally.modified[i], c.tally.accessed[i]
+		modified.Largest = topLargest(modified.Largest)
+		accessed.Largest = topLargest(accessed.Largest)
+		insights.Ages = append(insights.Ages, ageBucket{Label: limit.Label, Modified: modified, Accessed: accessed})
+	}
+	insights.TopDirs = topDirs(c.tally.topDirs, dirBySize)
+	insights.HeavyDirs = topDirs(c.tally.heavyDirs, dirByOwn)
+	insights.Errors = c.errors
+	insights.ErrorCount = c.errorCount
+	insights.Users = ownerResult(c.tally.users, lookupUserName)
+	insights.Groups = ownerResult(c.tally.groups, lookupGroupName)
+	return insights
+}
+
+// appendLargest keeps roughly the top maxCategoryFiles entries, trimming lazily.
+func appendLargest(files []fileEntry, file fileEntry) []fileEntry {
+	files = append(files, file)
+	if len(files) >= maxCategoryFiles*2 {
+		files = topLargest(files)
+	}
+	return files
+}
+
+func topLargest(files []fileEntry) []fileEntry {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if len(files) > maxCategoryFiles {
+		files = files[:maxCategoryFiles]
+	}
+	return files
+}
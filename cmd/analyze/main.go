@@ -1,19 +1,18 @@
-//go:build darwin
-
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tw93/mole/internal/disks"
 )
 
 type dirEntry struct {
@@ -22,6 +21,7 @@ type dirEntry struct {
 	Size       int64
 	IsDir      bool
 	LastAccess time.Time
+	Cleanable  bool // Rebuildable artifact, decided by markCleanable when the entries are loaded
 }
 
 type fileEntry struct {
@@ -34,12 +34,14 @@ type scanResult struct {
 	Entries    []dirEntry
 	LargeFiles []fileEntry
 	TotalSize  int64
+	Insights   *scanInsights
 }
 
 type cacheEntry struct {
 	Entries    []dirEntry
 	LargeFiles []fileEntry
 	TotalSize  int64
+	Insights   *scanInsights
 	ModTime    time.Time
 	ScanTime   time.Time
 }
@@ -49,6 +51,7 @@ type historyEntry struct {
 	Entries       []dirEntry
 	LargeFiles    []fileEntry
 	TotalSize     int64
+	Insights      *scanInsights
 	Selected      int
 	EntryOffset   int
 	LargeSelected int
@@ -71,10 +74,8 @@ type overviewSizeMsg struct {
 type tickMsg time.Time
 
 type deleteProgressMsg struct {
-	done  bool
-	err   error
-	count int64
-	path  string
+	done   bool
+	result deleteResult
 }
 
 type model struct {
@@ -96,8 +97,32 @@ type model struct {
 	isOverview           bool
 	deleteConfirm        bool
 	deleteTarget         *dirEntry
+	deleteBatch          []dirEntry          // Multiple targets confirmed together (sweep selection)
+	deleteTyped          *textInput          // Non-nil while the guard wants the name typed
+	deleteWarnings       []string            // Why the typed confirmation was required
+	deleteNotice         string              // Why the last deletion was refused, stopped or incomplete
+	notice               string              // Outcome of the last action, cleared on the next key
+	marked               map[string]dirEntry // Entries picked for a deletion plan, by path
+	planInput            *textInput          // Non-nil while asking where to save the plan
+	transferInput        *textInput          // Non-nil while asking where to move or archive
+	transferKind         string              // "move" or "archive"
+	transferTargets      []dirEntry
+	transferring         string // "Moving" or "Archiving" while one runs
+	transferProgress     *transferProgress
+	transferCancel       context.CancelFunc
+	showCompress         bool                         // Compression savings column
+	compress             map[string]*compressEstimate // Estimates by path
+	compressLoading      map[string]bool
+	archive              *archiveView       // Archive whose contents are listed, nil on disk
+	archiveLoading       string             // Archive being read before it opens
+	archiveCancel        context.CancelFunc // Stops reading it
 	deleting             bool
-	deleteCount          *int64
+	deleteProgress       *deleteProgress
+	deleteCancel         context.CancelFunc // Stops the running deletion
+	deleteGuardCancel    context.CancelFunc // Set while the targets are checked before deleting
+	deleteFailures       []deleteFailure    // Paths the last deletion left behind
+	deleteFailureCount   int64
+	failureCursor        listCursor
 	cache                map[string]historyEntry
 	largeSelected        int
 	largeOffset          int
@@ -108,18 +133,155 @@ type model struct {
 	overviewCurrentPath  *string
 	overviewScanning     bool
 	overviewScanningSet  map[string]bool // Track which paths are currently being scanned
-	width                int             // Terminal width
-	height               int             // Terminal height
+	shortcutInput        *textInput      // Non-nil while typing a new overview shortcut
+	mounts               []disks.Volume  // Mounted filesystems listed below the overview shortcuts
+	volume               volumeUsageMsg  // Free space shown in the header, read when a directory loads
+	showSweep            bool            // Project artifact sweep view
+	sweepItems           []sweepItem
+	sweepCursor          listCursor
+	sweepAgeFilter       int // 0 = none, otherwise index+1 into sweepAgeFilters
+	insights             *scanInsights
+	showTypes            bool // File type breakdown view
+	typeCursor           listCursor
+	typeDrill            string // Category whose largest files are listed
+	typeFileCursor       listCursor
+	showAges             bool // File age histogram view
+	ageByAccess          bool // Bucket by last access instead of last modification
+	ageCursor            listCursor
+	ageDrill             bool // Listing the largest files of the selected bucket
+	ageFileCursor        listCursor
+	showTopDirs          bool // Flattened largest directories view
+	topDirsByOwn         bool // Rank by own size instead of total size
+	topDirCursor         listCursor
+	showOwners           bool // Usage per owning user or group
+	ownersByGroup        bool
+	ownerCursor          listCursor
+	ownerDrill           bool // Listing the largest directories of the selected owner
+	ownerDirCursor       listCursor
+	showErrors           bool // Paths the scan could not read
+	showGit              bool // Git work tree breakdown
+	gitReport            *gitReport
+	gitCursor            listCursor
+	gitCancel            context.CancelFunc // Set while git is being read
+	showPackages         bool               // Dependency directories grouped by package
+	packages             []packageStat      // One row per package version
+	packagesByName       []packageStat      // One row per package
+	packagesGrouped      bool
+	packagesLoading      bool
+	packageProgress      *packageProgress
+	packageCursor        listCursor
+	packageDrill         bool // Listing the copies of the selected package
+	packageCopyCursor    listCursor
+	showVersions         bool          // Old SDK and artifact versions in toolchain caches
+	versionItems         []versionItem // Grouped by SDK or artifact, newest first
+	versionCursor        listCursor
+	errorCursor          listCursor
+	showTree             bool             // Indented tree layout
+	treeExpanded         map[string]bool  // Expanded directories, by path
+	treeLoading          map[string]bool  // Subtrees being scanned for expansion
+	treeErrors           map[string]error // Subtrees that could not be scanned, retried on the next expand
+	treeCursor           listCursor
+	showTreemap          bool // Treemap layout, selection shared with the list
+	showDetails          bool // Metadata pane beside the list
+	details              map[string]*entryDetails
+	detailsLoading       map[string]bool
+	detailsWalking       string             // Path whose directory walk is running
+	detailsCancel        context.CancelFunc // Stops that walk once the selection moves on
+	width                int                // Terminal width
+	height               int                // Terminal height
 }
 
 func (m model) inOverviewMode() bool {
 	return m.isOverview && m.path == "/"
 }
 
+// rowCount is the number of selectable rows; the overview also lists mounts.
+func (m model) rowCount() int {
+	if m.inOverviewMode() {
+		return len(m.entries) + len(m.mounts)
+	}
+	return len(m.entries)
+}
+
+// selectedEntry returns the highlighted row, mapping overview mount rows to
+// directory entries for their mount points.
+func (m model) selectedEntry() (dirEntry, bool) {
+	if m.selected < 0 || m.selected >= m.rowCount() {
+		return dirEntry{}, false
+	}
+	if m.selected < len(m.entries) {
+		return m.entries[m.selected], true
+	}
+	mount := m.mounts[m.selected-len(m.entries)]
+	return dirEntry{Name: mount.Mount, Path: mount.Mount, IsDir: true, Size: int64(mount.Used)}, true
+}
+
 func main() {
+	warm := flag.Bool("warm", false, "scan paths (default: overview shortcuts) in the background and refresh the cache")
+	warmDepth := flag.Int("warm-depth", 0, "with --warm, also cache subdirectories this many levels down; each level rereads the tree below it")
+	htmlReport := flag.String("html", "", "write a self-contained HTML report of the path to this file and exit")
+	serve := flag.String("serve", "", "serve a local web UI for the path on this loopback address, e.g. 127.0.0.1:8080")
+	history := flag.Bool("history", false, "print the log of deletions made from the analyzer (optionally below a path) and exit")
+	apply := flag.String("apply", "", "delete the entries of a JSON plan saved with P that are unchanged since, then exit")
+	flag.Parse()
+
+	if *apply != "" {
+		if err := runApplyPlan(os.Stdout, *apply); err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer apply: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *history {
+		if err := runHistory(os.Stdout, flag.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer history: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *warm {
+		if err := runWarmCache(flag.Args(), *warmDepth); err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer warm: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	target := os.Getenv("MO_ANALYZE_PATH")
-	if target == "" && len(os.Args) > 1 {
-		target = os.Args[1]
+	if target == "" && flag.NArg() > 0 {
+		target = flag.Arg(0)
+	}
+
+	if *htmlReport != "" {
+		if target == "" {
+			target = "."
+		}
+		root, err := filepath.Abs(target)
+		if err == nil {
+			err = runHTMLReport(*htmlReport, root)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer html: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *serve != "" {
+		if target == "" {
+			target = "."
+		}
+		root, err := filepath.Abs(target)
+		if err == nil {
+			err = runWebServer(*serve, root)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer serve: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	var abs string
@@ -192,59 +354,9 @@ func newModel(path string, isOverview bool) model {
 	return m
 }
 
-func createOverviewEntries() []dirEntry {
-	home := os.Getenv("HOME")
-	entries := []dirEntry{}
-
-	if home != "" {
-		entries = append(entries,
-			dirEntry{Name: "Home (~)", Path: home, IsDir: true, Size: -1},
-			dirEntry{Name: "Library (~/Library)", Path: filepath.Join(home, "Library"), IsDir: true, Size: -1},
-		)
-	}
-
-	entries = append(entries,
-		dirEntry{Name: "Applications", Path: "/Applications", IsDir: true, Size: -1},
-		dirEntry{Name: "System Library", Path: "/Library", IsDir: true, Size: -1},
-	)
-
-	// Add Volumes shortcut only when it contains real mounted folders (e.g., external disks)
-	if hasUsefulVolumeMounts("/Volumes") {
-		entries = append(entries, dirEntry{Name: "Volumes", Path: "/Volumes", IsDir: true, Size: -1})
-	}
-
-	return entries
-}
-
-func hasUsefulVolumeMounts(path string) bool {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return false
-	}
-
-	for _, entry := range entries {
-		name := entry.Name()
-		// Skip hidden control entries for Spotlight/TimeMachine etc.
-		if strings.HasPrefix(name, ".") {
-			continue
-		}
-
-		info, err := os.Lstat(filepath.Join(path, name))
-		if err != nil {
-			continue
-		}
-		if info.Mode()&fs.ModeSymlink != 0 {
-			continue // Ignore the synthetic MacintoshHD link
-		}
-		if info.IsDir() {
-			return true
-		}
-	}
-	return false
-}
-
 func (m *model) hydrateOverviewEntries() {
 	m.entries = createOverviewEntries()
+	m.mounts = collectMounts()
 	if m.overviewSizeCache == nil {
 		m.overviewSizeCache = make(map[string]int64)
 	}
@@ -343,6 +455,7 @@ func (m model) scanCmd(path string) tea.Cmd {
 				Entries:    cached.Entries,
 				LargeFiles: cached.LargeFiles,
 				TotalSize:  cached.TotalSize,
+				Insights:   cached.Insights,
 			}
 			return scanResultMsg{result: result, err: nil}
 		}
@@ -380,43 +493,118 @@ func tickCmd() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.updateKey(msg)
+		next, cmd := m.updateKey(msg)
+		if nm, ok := next.(model); ok {
+			return nm, tea.Batch(cmd, nm.requestDetails(), nm.requestCompress())
+		}
+		return next, cmd
+	case detailsRequestMsg:
+		if path, ok := m.detailsTarget(); !ok || path != msg.path || m.details[path] != nil || m.detailsLoading[path] {
+			return m, nil
+		}
+		return m, m.loadDetails(msg.path)
+	case compressMsg:
+		if m.compress != nil {
+			m.compress[msg.estimate.Path] = msg.estimate
+			delete(m.compressLoading, msg.estimate.Path)
+		}
+		return m, nil
+	case detailsMsg:
+		if !m.detailsLoading[msg.details.Path] {
+			// The walk was cancelled for a newer selection
+			return m, nil
+		}
+		if !msg.details.Counting {
+			delete(m.detailsLoading, msg.details.Path)
+		}
+		if m.details != nil {
+			m.details[msg.details.Path] = msg.details
+		}
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	case transferDoneMsg:
+		return m.handleTransferDone(msg)
+	case archiveLoadedMsg:
+		return m.handleArchiveLoaded(msg)
+	case deleteGuardMsg:
+		return m.handleDeleteGuard(msg)
+	case volumeUsageMsg:
+		if msg.path == m.path {
+			m.volume = msg
+		}
+		return m, nil
+	case gitReportMsg:
+		if msg.root != m.path || errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.gitCancel = nil
+		m.scanning = false
+		if msg.err != nil {
+			m.deleteNotice = fmt.Sprintf("Cannot analyze git repository: %v", msg.err)
+			return m, nil
+		}
+		m.mergeScanErrors(msg.unreadable)
+		m.gitReport = msg.report
+		m.gitCursor = listCursor{}
+		m.showGit = true
+		return m, nil
+	case packagesMsg:
+		if msg.root != m.path {
+			return m, nil
+		}
+		m.packagesLoading = false
+		if msg.err != nil {
+			m.showPackages = false
+			m.deleteNotice = fmt.Sprintf("Cannot read packages: %v", msg.err)
+			return m, nil
+		}
+		m.mergeScanErrors(msg.unreadable)
+		m.packages = msg.packages
+		m.packagesByName = groupPackagesByName(msg.packages)
+		return m, nil
+	case versionsMsg:
+		if msg.root != m.path {
+			return m, nil
+		}
+		m.scanning = false
+		m.mergeScanErrors(msg.unreadable)
+		m.versionItems = msg.items
+		m.versionCursor = listCursor{}
+		m.showVersions = true
+		old, _, _ := m.versionTotals()
+		m.status = fmt.Sprintf("Found %s in older versions", humanizeBytes(old))
+		return m, nil
+	case planSavedMsg:
+		if msg.err != nil {
+			m.notice = ""
+			m.deleteNotice = fmt.Sprintf("Cannot save plan: %v", msg.err)
+			return m, nil
+		}
+		m.marked = nil
+		m.notice = fmt.Sprintf("Saved plan of %d entries (%s) to %s", msg.count, humanizeBytes(msg.size), displayPath(msg.path))
+		return m, nil
 	case deleteProgressMsg:
 		if msg.done {
+			result := msg.result
 			m.deleting = false
-			if msg.err != nil {
-				m.status = fmt.Sprintf("Failed to delete: %v", msg.err)
-			} else {
-				if msg.path != "" {
-					m.removePathFromView(msg.path)
-					invalidateCache(msg.path)
-				}
-				invalidateCache(m.path)
-				m.status = fmt.Sprintf("Deleted %d items", msg.count)
-				// Mark all caches as dirty
-				for i := range m.history {
-					m.history[i].Dirty = true
-				}
-				for path := range m.cache {
-					entry := m.cache[path]
-					entry.Dirty = true
-					m.cache[path] = entry
-				}
-				// Refresh the view
-				m.scanning = true
-				// Reset scan counters for rescan
-				atomic.StoreInt64(m.filesScanned, 0)
-				atomic.StoreInt64(m.dirsScanned, 0)
-				atomic.StoreInt64(m.bytesScanned, 0)
-				if m.currentPath != nil {
-					*m.currentPath = ""
-				}
-				return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+			m.deleteCancel = nil
+			for _, path := range result.Removed {
+				m.removePathFromView(path)
+				delete(m.marked, path)
 			}
+			m.removeSweepPaths(result.Removed)
+			m.removePackagePaths(result.Removed)
+			m.removeVersionPaths(result.Removed)
+			m.deleteFailures = result.Failures
+			m.deleteFailureCount = result.FailureCount
+			m.failureCursor = listCursor{}
+			if result.Cancelled || result.FailureCount > 0 {
+				m.deleteNotice = deleteSummary(result)
+			}
+			return m, m.rescanAfterChange(result.Targets)
 		}
 		return m, nil
 	case scanResultMsg:
@@ -428,10 +616,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.entries = msg.result.Entries
 		m.largeFiles = msg.result.LargeFiles
 		m.totalSize = msg.result.TotalSize
+		m.insights = msg.result.Insights
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		m.clampEntrySelection()
 		m.clampLargeSelection()
 		m.cache[m.path] = cacheSnapshot(m)
+		var cmd tea.Cmd
+		if m.showTree {
+			cmd = m.reloadExpandedTree()
+		} else if m.showTreemap {
+			cmd = m.loadTreemapChildren()
+		} else {
+			cmd = m.requestDetails()
+		}
+		cmd = tea.Batch(cmd, m.requestCompress(), volumeUsageCmd(m.path))
 		if m.totalSize > 0 {
 			if m.overviewSizeCache == nil {
 				m.overviewSizeCache = make(map[string]int64)
@@ -441,6 +639,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				_ = storeOverviewSize(path, size)
 			}(m.path, m.totalSize)
 		}
+		return m, cmd
+	case treeChildrenMsg:
+		delete(m.treeLoading, msg.path)
+		if msg.err != nil {
+			// Kept out of the cache so entering the directory scans it again
+			if m.treeErrors == nil {
+				m.treeErrors = make(map[string]error)
+			}
+			m.treeErrors[msg.path] = msg.err
+			return m, nil
+		}
+		m.cache[msg.path] = historyEntry{
+			Path:       msg.path,
+			Entries:    msg.result.Entries,
+			LargeFiles: msg.result.LargeFiles,
+			TotalSize:  msg.result.TotalSize,
+			Insights:   msg.result.Insights,
+		}
+		return m, nil
+	case sweepResultMsg:
+		if msg.root != m.path {
+			return m, nil
+		}
+		m.scanning = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Sweep failed: %v", msg.err)
+			return m, nil
+		}
+		m.sweepItems = msg.items
+		m.sweepCursor = listCursor{}
+		m.sweepAgeFilter = 0
+		m.showSweep = true
+		total, _, _, projects := m.sweepTotals()
+		m.status = fmt.Sprintf("Found %s of artifacts in %d projects", humanizeBytes(total), projects)
 		return m, nil
 	case overviewSizeMsg:
 		// Remove from scanning set
@@ -488,13 +720,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
-		if m.scanning || m.deleting || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
+		if m.scanning || m.deleting || m.deleteGuardCancel != nil || m.transferring != "" || m.packagesLoading || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
 			m.spinner = (m.spinner + 1) % len(spinnerFrames)
 			// Update delete progress status
-			if m.deleting && m.deleteCount != nil {
-				count := atomic.LoadInt64(m.deleteCount)
-				if count > 0 {
-					m.status = fmt.Sprintf("Deleting... %s items removed", formatNumber(count))
+			if m.deleting && m.deleteProgress != nil {
+				if items, _ := m.deleteProgress.snapshot(); items > 0 {
+					m.status = fmt.Sprintf("Deleting... %s items removed", formatNumber(items))
 				}
 			}
 			return m, tickCmd()
@@ -506,39 +737,101 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.deleting {
+		return m.updateDeletingKey(msg)
+	}
+	if m.transferring != "" {
+		return m.updateTransferringKey(msg)
+	}
+	if m.archiveLoading != "" {
+		return m.updateArchiveLoadingKey(msg)
+	}
+	if m.gitCancel != nil {
+		return m.updateGitLoadingKey(msg)
+	}
+	if m.deleteGuardCancel != nil {
+		return m.updateDeleteGuardKey(msg)
+	}
+	m.deleteNotice = ""
+	m.notice = ""
+	if m.deleteTyped != nil {
+		return m.updateDeleteTyped(msg)
+	}
+
 	// Handle delete confirmation
 	if m.deleteConfirm {
 		if msg.String() == "delete" || msg.String() == "backspace" {
-			// Confirm delete - start async deletion
-			if m.deleteTarget != nil {
-				m.deleteConfirm = false
-				m.deleting = true
-				var deleteCount int64
-				m.deleteCount = &deleteCount
-				targetPath := m.deleteTarget.Path
-				targetName := m.deleteTarget.Name
-				m.deleteTarget = nil
-				m.status = fmt.Sprintf("Deleting %s...", targetName)
-				return m, tea.Batch(deletePathCmd(targetPath, m.deleteCount), tickCmd())
-			}
-			m.deleteConfirm = false
-			m.deleteTarget = nil
-			return m, nil
+			return m.guardDelete()
 		} else if msg.String() == "esc" || msg.String() == "q" {
 			// Cancel delete with ESC or Q
 			m.status = "Cancelled"
 			m.deleteConfirm = false
 			m.deleteTarget = nil
+			m.deleteBatch = nil
 			return m, nil
 		} else {
 			// Any other key also cancels
 			m.status = "Cancelled"
 			m.deleteConfirm = false
 			m.deleteTarget = nil
+			m.deleteBatch = nil
 			return m, nil
 		}
 	}
 
+	if m.shortcutInput != nil {
+		return m.updateShortcutInput(msg)
+	}
+	if m.planInput != nil {
+		return m.updatePlanInput(msg)
+	}
+	if m.transferInput != nil {
+		return m.updateTransferInput(msg)
+	}
+	if len(m.deleteFailures) > 0 {
+		return m.updateDeleteFailuresKey(msg)
+	}
+	if m.showSweep {
+		return m.updateSweepKey(msg)
+	}
+	if m.showTypes {
+		return m.updateTypesKey(msg)
+	}
+	if m.showAges {
+		return m.updateAgesKey(msg)
+	}
+	if m.showTopDirs {
+		return m.updateTopDirsKey(msg)
+	}
+	if m.showOwners {
+		return m.updateOwnersKey(msg)
+	}
+	if m.showErrors {
+		return m.updateErrorsKey(msg)
+	}
+	if m.showGit {
+		return m.updateGitKey(msg)
+	}
+	if m.showPackages {
+		return m.updatePackagesKey(msg)
+	}
+	if m.showVersions {
+		return m.updateVersionsKey(msg)
+	}
+	if m.showTree {
+		return m.updateTreeKey(msg)
+	}
+	if m.showTreemap && !m.showLargeFiles {
+		if next, cmd, handled := m.updateTreemapKey(msg); handled {
+			return next, cmd
+		}
+	}
+	if m.inArchive() {
+		if next, cmd, handled := m.updateArchiveKey(msg); handled {
+			return next, cmd
+		}
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
@@ -556,7 +849,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.largeOffset = m.largeSelected
 				}
 			}
-		} else if len(m.entries) > 0 && m.selected > 0 {
+		} else if m.rowCount() > 0 && m.selected > 0 {
 			m.selected--
 			if m.selected < m.offset {
 				m.offset = m.selected
@@ -571,7 +864,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					m.largeOffset = m.largeSelected - viewport + 1
 				}
 			}
-		} else if len(m.entries) > 0 && m.selected < len(m.entries)-1 {
+		} else if m.rowCount() > 0 && m.selected < m.rowCount()-1 {
 			m.selected++
 			viewport := calculateViewport(m.height, false)
 			if m.selected >= m.offset+viewport {
@@ -580,6 +873,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter", "right", "l":
 		if m.showLargeFiles {
+			if !m.inArchive() && m.largeSelected < len(m.largeFiles) && isBrowsableArchive(m.largeFiles[m.largeSelected].Path) {
+				return m.openArchive(m.largeFiles[m.largeSelected].Path)
+			}
 			return m, nil
 		}
 		return m.enterSelectedDir()
@@ -603,6 +899,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.largeSelected = last.LargeSelected
 		m.largeOffset = last.LargeOffset
 		m.isOverview = false
+		m.leaveArchiveIfOutside()
 		if last.Dirty {
 			m.status = "Scanning..."
 			m.scanning = true
@@ -611,6 +908,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.entries = last.Entries
 		m.largeFiles = last.LargeFiles
 		m.totalSize = last.TotalSize
+		m.insights = last.Insights
 		m.clampEntrySelection()
 		m.clampLargeSelection()
 		if len(m.entries) == 0 {
@@ -623,20 +921,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		m.scanning = false
-		return m, nil
+		return m, volumeUsageCmd(m.path)
 	case "r":
-		// Invalidate cache before rescanning to ensure fresh data
-		invalidateCache(m.path)
-		m.status = "Refreshing..."
-		m.scanning = true
-		// Reset scan counters for refresh
-		atomic.StoreInt64(m.filesScanned, 0)
-		atomic.StoreInt64(m.dirsScanned, 0)
-		atomic.StoreInt64(m.bytesScanned, 0)
-		if m.currentPath != nil {
-			*m.currentPath = ""
-		}
-		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+		return m.refreshCurrentPath()
 	case "L":
 		m.showLargeFiles = !m.showLargeFiles
 		if m.showLargeFiles {
@@ -651,39 +938,157 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				go func(path string) {
 					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 					defer cancel()
-					_ = exec.CommandContext(ctx, "open", path).Run()
+					_ = openPathCommand(ctx, path).Run()
 				}(selected.Path)
 				m.status = fmt.Sprintf("Opening %s...", selected.Name)
 			}
-		} else if len(m.entries) > 0 {
-			selected := m.entries[m.selected]
+		} else if selected, ok := m.selectedEntry(); ok {
 			go func(path string) {
 				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 				defer cancel()
-				_ = exec.CommandContext(ctx, "open", path).Run()
+				_ = openPathCommand(ctx, path).Run()
 			}(selected.Path)
 			m.status = fmt.Sprintf("Opening %s...", selected.Name)
 		}
 	case "f", "F":
-		// Reveal selected entry in Finder
+		// Reveal selected entry in Finder (or the file manager on Linux)
 		if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				selected := m.largeFiles[m.largeSelected]
 				go func(path string) {
 					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 					defer cancel()
-					_ = exec.CommandContext(ctx, "open", "-R", path).Run()
+					_ = revealPathCommand(ctx, path).Run()
 				}(selected.Path)
-				m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+				m.status = fmt.Sprintf("Showing %s in %s...", selected.Name, fileManagerName)
 			}
-		} else if len(m.entries) > 0 {
-			selected := m.entries[m.selected]
+		} else if selected, ok := m.selectedEntry(); ok {
 			go func(path string) {
 				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 				defer cancel()
-				_ = exec.CommandContext(ctx, "open", "-R", path).Run()
+				_ = revealPathCommand(ctx, path).Run()
 			}(selected.Path)
-			m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+			m.status = fmt.Sprintf("Showing %s in %s...", selected.Name, fileManagerName)
+		}
+	case "t":
+		// File type breakdown of the current directory
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showTypes = true
+			m.typeDrill = ""
+			m.typeCursor = listCursor{}
+		}
+	case "A":
+		// Histogram of file ages under the current directory
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showAges = true
+			m.ageDrill = false
+			m.ageCursor = listCursor{}
+		}
+	case "D":
+		// Largest directories at any depth below the current one
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showTopDirs = true
+			m.topDirCursor = listCursor{}
+		}
+	case "U":
+		// Usage per owning user and group
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showOwners = true
+			m.ownerDrill = false
+			m.ownerCursor = listCursor{}
+		}
+	case "E":
+		// Paths the scan could not read
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showErrors = true
+			m.errorCursor = listCursor{}
+		}
+	case "G":
+		// Where the bytes of a git work tree go
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			return m.startGitReport()
+		}
+	case "N":
+		// Packages in the dependency directories below the current one
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			return m.startPackages()
+		}
+	case "S":
+		// Older versions piled up in toolchain caches such as .nvm or .m2
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			return m.startVersions()
+		}
+	case "v":
+		// Indented tree layout of the current directory
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showTree = true
+			m.treeCursor = listCursor{Selected: m.selected, Offset: m.offset}
+		}
+	case "M":
+		// Treemap of the current directory
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			m.showTreemap = true
+			return m, m.loadTreemapChildren()
+		}
+	case "C":
+		// Sweep the current tree for rebuildable project artifacts
+		if !m.inOverviewMode() && !m.showLargeFiles {
+			return m.startSweep()
+		}
+	case " ":
+		// Mark the selected entry for a deletion plan
+		if !m.inOverviewMode() {
+			m.toggleMark()
+		}
+	case "P":
+		// Save the marked entries as a plan to review and --apply later
+		if len(m.marked) > 0 {
+			name := fmt.Sprintf("~/mole-plan-%s.json", time.Now().Format("20060102-150405"))
+			m.planInput = &textInput{Prompt: fmt.Sprintf("Save plan of %d entries (%s) to:", len(m.marked), humanizeBytes(m.markedSize())), Value: name}
+		}
+	case "x", "X":
+		// Move the selection or the marked entries to another directory
+		return m.startTransferInput("move")
+	case "z", "Z":
+		// Pack the selection or the marked entries into a compressed tarball
+		return m.startTransferInput("archive")
+	case "%":
+		// Column with the space compression would save per entry
+		if !m.inOverviewMode() {
+			m.showCompress = !m.showCompress
+			if m.showCompress && m.compress == nil {
+				m.resetCompress()
+			}
+		}
+	case "i":
+		// Metadata pane for the selected entry
+		if !m.inOverviewMode() {
+			m.showDetails = !m.showDetails
+		}
+	case "+":
+		// Pin a directory to the overview screen
+		if m.inOverviewMode() {
+			m.shortcutInput = &textInput{Prompt: "Add shortcut:", Value: "~/"}
+			return m, nil
+		}
+		if !m.showLargeFiles {
+			if err := addOverviewShortcut(m.path); err != nil {
+				m.status = fmt.Sprintf("Cannot add shortcut: %v", err)
+			} else {
+				m.status = fmt.Sprintf("Pinned %s to overview", displayPath(m.path))
+			}
+		}
+	case "-":
+		// Remove the selected overview shortcut
+		if m.inOverviewMode() && m.selected < len(m.entries) {
+			selected := m.entries[m.selected]
+			if err := removeOverviewShortcut(selected.Path); err != nil {
+				m.status = fmt.Sprintf("Cannot remove shortcut: %v", err)
+				return m, nil
+			}
+			m.hydrateOverviewEntries()
+			m.clampEntrySelection()
+			m.status = fmt.Sprintf("Removed %s from overview", selected.Name)
 		}
 	case "delete", "backspace":
 		// Delete selected file or directory
@@ -707,6 +1112,89 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m model) refreshCurrentPath() (tea.Model, tea.Cmd) {
+	// Invalidate cache before rescanning to ensure fresh data
+	invalidateCache(m.path)
+	m.details = nil
+	m.resetCompress()
+	m.status = "Refreshing..."
+	m.scanning = true
+	// Reset scan counters for refresh
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+// rescanAfterChange drops every cached level between the view and paths, marks
+// the history dirty and rescans the current directory. Partial deletions and
+// moves change sizes too, so this runs whether or not the paths are gone.
+func (m *model) rescanAfterChange(paths []string) tea.Cmd {
+	for _, path := range paths {
+		invalidateCache(path)
+		for dir := filepath.Dir(path); strings.HasPrefix(dir, m.path) && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			invalidateCache(dir)
+			if dir == m.path {
+				break
+			}
+		}
+		invalidateCache(filepath.Dir(path))
+	}
+	invalidateCache(m.path)
+	m.details = nil
+	m.resetCompress()
+	// Mark all caches as dirty
+	for i := range m.history {
+		m.history[i].Dirty = true
+	}
+	for path := range m.cache {
+		entry := m.cache[path]
+		entry.Dirty = true
+		m.cache[path] = entry
+	}
+	// Refresh the view
+	m.scanning = true
+	// Reset scan counters for rescan
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	return tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+func (m model) updateShortcutInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.shortcutInput.handleKey(msg)
+	if cancelled {
+		m.shortcutInput = nil
+		m.status = "Cancelled"
+		return m, nil
+	}
+	if !submitted {
+		return m, nil
+	}
+	value := strings.TrimSpace(m.shortcutInput.Value)
+	m.shortcutInput = nil
+	if err := addOverviewShortcut(value); err != nil {
+		m.status = fmt.Sprintf("Cannot add shortcut: %v", err)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Added %s to overview", value)
+	if !m.inOverviewMode() {
+		return m, nil
+	}
+	m.hydrateOverviewEntries()
+	m.clampEntrySelection()
+	if cmd := m.scheduleOverviewScans(); cmd != nil {
+		return m, tea.Batch(cmd, tickCmd())
+	}
+	return m, nil
+}
+
 func (m *model) switchToOverviewMode() tea.Cmd {
 	m.isOverview = true
 	m.path = "/"
@@ -717,6 +1205,20 @@ func (m *model) switchToOverviewMode() tea.Cmd {
 	m.largeOffset = 0
 	m.deleteConfirm = false
 	m.deleteTarget = nil
+	m.deleteBatch = nil
+	m.showSweep = false
+	m.showTypes = false
+	m.showAges = false
+	m.showTopDirs = false
+	m.showOwners = false
+	m.showErrors = false
+	m.showGit = false
+	m.showPackages = false
+	m.showVersions = false
+	m.showTree = false
+	m.showTreemap = false
+	m.archive = nil
+	m.insights = nil
 	m.selected = 0
 	m.offset = 0
 	m.hydrateOverviewEntries()
@@ -730,49 +1232,99 @@ func (m *model) switchToOverviewMode() tea.Cmd {
 }
 
 func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
-	if len(m.entries) == 0 {
+	selected, ok := m.selectedEntry()
+	if !ok {
 		return m, nil
 	}
-	selected := m.entries[m.selected]
 	if selected.IsDir {
 		if !m.inOverviewMode() {
 			m.history = append(m.history, snapshotFromModel(m))
 		}
-		m.path = selected.Path
-		m.selected = 0
-		m.offset = 0
-		m.status = "Scanning..."
-		m.scanning = true
-		m.isOverview = false
-
-		// Reset scan counters for new scan
-		atomic.StoreInt64(m.filesScanned, 0)
-		atomic.StoreInt64(m.dirsScanned, 0)
-		atomic.StoreInt64(m.bytesScanned, 0)
-		if m.currentPath != nil {
-			*m.currentPath = ""
-		}
-
-		if cached, ok := m.cache[m.path]; ok && !cached.Dirty {
-			m.entries = cloneDirEntries(cached.Entries)
-			m.largeFiles = cloneFileEntries(cached.LargeFiles)
-			m.totalSize = cached.TotalSize
-			m.selected = cached.Selected
-			m.offset = cached.EntryOffset
-			m.largeSelected = cached.LargeSelected
-			m.largeOffset = cached.LargeOffset
-			m.clampEntrySelection()
-			m.clampLargeSelection()
-			m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
-			m.scanning = false
+		if m.inArchive() {
+			m.showArchiveDir(selected.Path)
 			return m, nil
 		}
-		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+		next, cmd := m.openDir(selected.Path)
+		if dependencyEcosystem(selected.Path) != "" {
+			// Package stores read better grouped by package than as raw directories
+			next, packagesCmd := next.(model).startPackages()
+			return next, tea.Batch(cmd, packagesCmd)
+		}
+		return next, cmd
+	}
+	if !m.inArchive() && isBrowsableArchive(selected.Path) {
+		return m.openArchive(selected.Path)
 	}
 	m.status = fmt.Sprintf("File: %s (%s)", selected.Name, humanizeBytes(selected.Size))
 	return m, nil
 }
 
+// jumpToDir opens a directory below the current one, pushing every level in
+// between onto the history so that going back walks up one level at a time.
+func (m model) jumpToDir(target string) (tea.Model, tea.Cmd) {
+	rel, err := filepath.Rel(m.path, target)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return m, nil
+	}
+	m.history = append(m.history, snapshotFromModel(m))
+	parts := strings.Split(rel, string(filepath.Separator))
+	dir := m.path
+	for i, part := range parts[:len(parts)-1] {
+		dir = filepath.Join(dir, part)
+		cached, ok := m.cache[dir]
+		if !ok || cached.Dirty {
+			// Scanned on the way back
+			m.history = append(m.history, historyEntry{Path: dir, Dirty: true})
+			continue
+		}
+		next := filepath.Join(dir, parts[i+1])
+		for idx, entry := range cached.Entries {
+			if entry.Path == next {
+				cached.Selected = idx
+				cached.EntryOffset = 0
+				break
+			}
+		}
+		m.history = append(m.history, cached)
+	}
+	return m.openDir(target)
+}
+
+// openDir shows path, from the in-memory cache when possible.
+func (m model) openDir(path string) (tea.Model, tea.Cmd) {
+	m.path = path
+	m.selected = 0
+	m.offset = 0
+	m.status = "Scanning..."
+	m.scanning = true
+	m.isOverview = false
+
+	// Reset scan counters for new scan
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+
+	if cached, ok := m.cache[m.path]; ok && !cached.Dirty {
+		m.entries = cloneDirEntries(cached.Entries)
+		m.largeFiles = cloneFileEntries(cached.LargeFiles)
+		m.totalSize = cached.TotalSize
+		m.insights = cached.Insights
+		m.selected = cached.Selected
+		m.offset = cached.EntryOffset
+		m.largeSelected = cached.LargeSelected
+		m.largeOffset = cached.LargeOffset
+		m.clampEntrySelection()
+		m.clampLargeSelection()
+		m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
+		m.scanning = false
+		return m, volumeUsageCmd(m.path)
+	}
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
 func (m model) View() string {
 	var b strings.Builder
 	fmt.Fprintln(&b)
@@ -819,28 +1371,66 @@ func (m model) View() string {
 		}
 	} else {
 		fmt.Fprintf(&b, "%sAnalyze Disk%s  %s%s%s", colorPurple, colorReset, colorGray, displayPath(m.path), colorReset)
-		if !m.scanning {
+		if m.showSweep && !m.scanning {
+			total, marked, markedCount, projects := m.sweepTotals()
+			fmt.Fprintf(&b, "  |  Artifacts: %s in %d projects", humanizeBytes(total), projects)
+			if markedCount > 0 {
+				fmt.Fprintf(&b, "  |  %sSelected: %d (%s)%s", colorYellow, markedCount, humanizeBytes(marked), colorReset)
+			}
+		} else if !m.scanning {
 			fmt.Fprintf(&b, "  |  Total: %s", humanizeBytes(m.totalSize))
 		}
-		fmt.Fprintf(&b, "\n\n")
+		if m.inArchive() {
+			fmt.Fprintf(&b, "  |  %sPacked: %s%s", colorGray, humanizeBytes(m.archive.Packed), colorReset)
+		} else if m.volume.ok && (m.volume.path == m.path || m.scanning) {
+			fmt.Fprintf(&b, "  |  %sFree: %s of %s%s", colorGray, humanizeBytes(int64(m.volume.free)), humanizeBytes(int64(m.volume.total)), colorReset)
+		}
+		if banner := m.errorBanner(); banner != "" && !m.scanning {
+			fmt.Fprintf(&b, "\n%s\n", banner)
+		} else {
+			fmt.Fprintf(&b, "\n\n")
+		}
 	}
 
 	if m.deleting {
 		// Show delete progress
-		count := int64(0)
-		if m.deleteCount != nil {
-			count = atomic.LoadInt64(m.deleteCount)
+		var items, bytes int64
+		if m.deleteProgress != nil {
+			items, bytes = m.deleteProgress.snapshot()
 		}
 
-		fmt.Fprintf(&b, "%s%s%s%s Deleting: %s%s items%s removed, please wait...\n",
+		fmt.Fprintf(&b, "%s%s%s%s Deleting: %s%s items%s, %s%s%s removed\n",
 			colorCyan, colorBold,
 			spinnerFrames[m.spinner],
 			colorReset,
-			colorYellow, formatNumber(count), colorReset)
+			colorYellow, formatNumber(items), colorReset,
+			colorGreen, humanizeBytes(bytes), colorReset)
+		if m.deleteCancel == nil {
+			fmt.Fprintf(&b, "%sStopping, finishing the files in progress...%s\n", colorGray, colorReset)
+		} else {
+			fmt.Fprintf(&b, "%sESC to stop, whatever is left stays in place%s\n", colorGray, colorReset)
+		}
 
 		return b.String()
 	}
 
+	if m.transferring != "" {
+		var items, bytes int64
+		if m.transferProgress != nil {
+			items, bytes = m.transferProgress.snapshot()
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s: %s%s items%s, %s%s%s copied\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, m.transferring,
+			colorYellow, formatNumber(items), colorReset,
+			colorGreen, humanizeBytes(bytes), colorReset)
+		if m.transferCancel == nil {
+			fmt.Fprintf(&b, "%sStopping, removing the partial copy...%s\n", colorGray, colorReset)
+		} else {
+			fmt.Fprintf(&b, "%sESC to stop, the originals stay in place%s\n", colorGray, colorReset)
+		}
+		return b.String()
+	}
+
 	if m.scanning {
 		filesScanned, dirsScanned, bytesScanned := m.getScanProgress()
 
@@ -864,7 +1454,30 @@ func (m model) View() string {
 		return b.String()
 	}
 
-	if m.showLargeFiles {
+	listStart := b.Len()
+	if len(m.deleteFailures) > 0 {
+		m.renderDeleteFailures(&b)
+	} else if m.showSweep {
+		m.renderSweep(&b)
+	} else if m.showTypes {
+		m.renderTypes(&b)
+	} else if m.showAges {
+		m.renderAges(&b)
+	} else if m.showTopDirs {
+		m.renderTopDirs(&b)
+	} else if m.showOwners {
+		m.renderOwners(&b)
+	} else if m.showErrors {
+		m.renderErrors(&b)
+	} else if m.showGit {
+		m.renderGit(&b)
+	} else if m.showPackages {
+		m.renderPackages(&b)
+	} else if m.showVersions {
+		m.renderVersions(&b)
+	} else if m.showTree {
+		m.renderTree(&b)
+	} else if m.showLargeFiles {
 		if len(m.largeFiles) == 0 {
 			fmt.Fprintln(&b, "  No large files found (>=100MB)")
 		} else {
@@ -900,12 +1513,18 @@ func (m model) View() string {
 				}
 				size := humanizeBytes(file.Size)
 				bar := coloredProgressBar(file.Size, maxLargeSize, 0)
-				fmt.Fprintf(&b, "%s%s%2d.%s %s  |  📄 %s%s%s  %s%10s%s\n",
-					entryPrefix, numColor, idx+1, colorReset, bar, nameColor, paddedPath, colorReset, sizeColor, size, colorReset)
+				icon := "📄"
+				if m.isMarked(file.Path) {
+					icon = "📌"
+				}
+				fmt.Fprintf(&b, "%s%s%2d.%s %s  |  %s %s%s%s  %s%10s%s\n",
+					entryPrefix, numColor, idx+1, colorReset, bar, icon, nameColor, paddedPath, colorReset, sizeColor, size, colorReset)
 			}
 		}
+	} else if m.showTreemap {
+		m.renderTreemap(&b)
 	} else {
-		if len(m.entries) == 0 {
+		if len(m.entries) == 0 && !m.inOverviewMode() {
 			fmt.Fprintln(&b, "  Empty directory")
 		} else {
 			if m.inOverviewMode() {
@@ -968,7 +1587,7 @@ func (m model) View() string {
 
 					// Priority: cleanable > unused time
 					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
+					if entry.Cleanable {
 						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
 					} else {
 						// For overview mode, get access time on-demand if not set
@@ -1016,6 +1635,9 @@ func (m model) View() string {
 					if entry.IsDir {
 						icon = "📁"
 					}
+					if m.isMarked(entry.Path) {
+						icon = "📌"
+					}
 					size := humanizeBytes(entry.Size)
 					name := trimName(entry.Name)
 					paddedName := padName(name, 28)
@@ -1056,7 +1678,7 @@ func (m model) View() string {
 
 					// Priority: cleanable > unused time
 					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
+					if entry.Cleanable {
 						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
 					} else {
 						// Get access time on-demand if not set
@@ -1069,6 +1691,9 @@ func (m model) View() string {
 						}
 					}
 
+					if label := m.compressLabel(entry.Path); label != "" {
+						size = fmt.Sprintf("%10s%s  %s", size, colorReset, label)
+					}
 					if hintLabel == "" {
 						fmt.Fprintf(&b, "%s%s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
 							entryPrefix, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
@@ -1083,18 +1708,119 @@ func (m model) View() string {
 		}
 	}
 
+	if m.inOverviewMode() && len(m.mounts) > 0 {
+		m.renderMounts(&b, len(m.entries))
+	}
+	if m.detailsPaneVisible() {
+		content := b.String()
+		b.Reset()
+		b.WriteString(content[:listStart])
+		b.WriteString(m.withDetailsPane(content[listStart:]))
+	}
+
 	fmt.Fprintln(&b)
-	if m.inOverviewMode() {
-		fmt.Fprintf(&b, "%s↑↓→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  Q Quit%s\n", colorGray, colorReset)
+	if len(m.deleteFailures) > 0 {
+		fmt.Fprintf(&b, "%s↑↓  |  F Show  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showSweep {
+		filter := "none"
+		if m.sweepAgeFilter < len(sweepAgeFilters) {
+			filter = sweepAgeFilters[m.sweepAgeFilter].Label
+		}
+		fmt.Fprintf(&b, "%s↑↓  |  Space Select  |  S Idle %s+  |  O Open  |  F Show  |  ⌫ Delete  |  ESC Back  |  Q Quit%s\n", colorGray, filter, colorReset)
+	} else if m.showTypes && m.typeDrill != "" {
+		fmt.Fprintf(&b, "%s↑↓  |  O Open  |  F Show  |  ⌫ Delete  |  ← Types  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showTypes {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Largest files  |  T Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showAges && m.ageDrill {
+		fmt.Fprintf(&b, "%s↑↓  |  O Open  |  F Show  |  ⌫ Delete  |  ← Ages  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showAges {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Largest files  |  Tab Modified/Accessed  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showTreemap && !m.showLargeFiles {
+		fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  B Back  |  O Open  |  F Show  |  ⌫ Delete  |  M List  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showTree {
+		fmt.Fprintf(&b, "%s↑↓  |  → Expand  |  ← Collapse  |  O Open  |  F Show  |  ⌫ Delete  |  V List  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showErrors {
+		fmt.Fprintf(&b, "%s↑↓  |  F Show  |  R Rescan  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showGit {
+		fmt.Fprintf(&b, "%s↑↓  |  R Rescan  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showPackages && m.packageDrill {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Jump  |  O Open  |  F Show  |  ⌫ Delete  |  ← Packages  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showPackages {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Copies  |  Tab Versions/Packages  |  R Rescan  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showVersions {
+		fmt.Fprintf(&b, "%s↑↓  |  Space Keep/Remove  |  O Open  |  F Show  |  ⌫ Delete marked  |  R Rescan  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showOwners && m.ownerDrill {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Jump  |  O Open  |  F Show  |  ← Owners  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showOwners {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Largest dirs  |  Tab Users/Groups  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.showTopDirs {
+		fmt.Fprintf(&b, "%s↑↓  |  Enter Jump  |  Tab Total/Own  |  ⌫ Delete  |  ESC Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.inOverviewMode() {
+		fmt.Fprintf(&b, "%s↑↓→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  + Add  |  - Remove  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.inArchive() && m.showLargeFiles {
+		fmt.Fprintf(&b, "%s↑↓  |  L Back  |  Q Quit%s\n", colorGray, colorReset)
+	} else if m.inArchive() {
+		if largeFileCount := len(m.largeFiles); largeFileCount > 0 {
+			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  L Large(%d)  |  Q Quit%s\n", colorGray, largeFileCount, colorReset)
+		} else {
+			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  Q Quit%s\n", colorGray, colorReset)
+		}
+		fmt.Fprintf(&b, "%sRead-only view of %s, ← at the top leaves it%s\n", colorGray, displayPath(m.archive.Path), colorReset)
 	} else if m.showLargeFiles {
-		fmt.Fprintf(&b, "%s↑↓  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  L Back  |  Q Quit%s\n", colorGray, colorReset)
+		fmt.Fprintf(&b, "%s↑↓  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  X Move  |  Z Archive  |  Space Mark%s  |  I Info  |  L Back  |  Q Quit%s\n", colorGray, m.planHint(), colorReset)
 	} else {
 		largeFileCount := len(m.largeFiles)
 		if largeFileCount > 0 {
-			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  L Large(%d)  |  Q Quit%s\n", colorGray, largeFileCount, colorReset)
+			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  X Move  |  Z Archive  |  L Large(%d)  |  Q Quit%s\n", colorGray, largeFileCount, colorReset)
 		} else {
-			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  Q Quit%s\n", colorGray, colorReset)
+			fmt.Fprintf(&b, "%s↑↓←→  |  Enter  |  R Refresh  |  O Open  |  F Show  |  ⌫ Delete  |  X Move  |  Z Archive  |  Q Quit%s\n", colorGray, colorReset)
 		}
+		fmt.Fprintf(&b, "%sI Info  |  T Types  |  A Ages  |  D Dirs  |  U Owners  |  V Tree  |  M Map  |  C Sweep  |  G Git  |  N Packages  |  %% Compress%s  |  Space Mark%s%s\n", colorGray, m.versionsHint(), m.planHint(), colorReset)
+	}
+	if m.shortcutInput != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.shortcutInput.view())
+	}
+	if m.planInput != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.planInput.view())
+	}
+	if m.transferInput != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.transferInput.view())
+	}
+	if m.notice != "" {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%s%s%s\n", colorGreen, m.notice, colorReset)
+	}
+	if m.deleteGuardCancel != nil {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%s%s Checking before deleting...%s  %sESC cancel%s\n",
+			colorYellow, spinnerFrames[m.spinner], colorReset, colorGray, colorReset)
+		return b.String()
+	}
+	if m.deleteTyped != nil {
+		fmt.Fprintln(&b)
+		for _, warning := range m.deleteWarnings {
+			fmt.Fprintf(&b, "%s⚠ %s%s\n", colorYellow, warning, colorReset)
+		}
+		b.WriteString(m.deleteTyped.view())
+		return b.String()
+	}
+	if m.deleteConfirm && len(m.deleteBatch) > 0 {
+		var total int64
+		for _, entry := range m.deleteBatch {
+			total += entry.Size
+		}
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%sDelete:%s %d items (%s)  %sPress ⌫ again  |  ESC cancel%s\n",
+			colorRed, colorReset,
+			len(m.deleteBatch), humanizeBytes(total),
+			colorGray, colorReset)
+	}
+	if m.deleteNotice != "" {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%s%s%s\n", colorRed, m.deleteNotice, colorReset)
 	}
 	if m.deleteConfirm && m.deleteTarget != nil {
 		fmt.Fprintln(&b)
@@ -1107,19 +1833,20 @@ func (m model) View() string {
 }
 
 func (m *model) clampEntrySelection() {
-	if len(m.entries) == 0 {
+	rows := m.rowCount()
+	if rows == 0 {
 		m.selected = 0
 		m.offset = 0
 		return
 	}
-	if m.selected >= len(m.entries) {
-		m.selected = len(m.entries) - 1
+	if m.selected >= rows {
+		m.selected = rows - 1
 	}
 	if m.selected < 0 {
 		m.selected = 0
 	}
 	viewport := calculateViewport(m.height, false)
-	maxOffset := len(m.entries) - viewport
+	maxOffset := rows - viewport
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -1244,7 +1971,7 @@ func calculateViewport(termHeight int, isLargeFiles bool) int {
 	}
 
 	// Calculate reserved space for UI elements
-	reserved := 6 // header (3-4 lines) + footer (2 lines)
+	reserved := 7 // header (3-4 lines) + footer (3 lines)
 	if isLargeFiles {
 		reserved = 5 // Large files view has less overhead
 	}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/tw93/mole/internal/disks"
+)
+
+// collectMounts lists real mounted filesystems, the same ones as the disk
+// section of mo status, but without truncating the list.
+func collectMounts() []disks.Volume {
+	volumes, err := disks.List()
+	if err != nil {
+		return nil
+	}
+	return volumes
+}
+
+// volumeUsage reports free and total bytes of the filesystem holding path.
+func volumeUsage(path string) (free, total uint64, ok bool) {
+	usage, err := disk.Usage(path)
+	if err != nil || usage.Total == 0 {
+		return 0, 0, false
+	}
+	return usage.Free, usage.Total, true
+}
+
+// volumeUsageMsg carries the free space of the filesystem holding path.
+type volumeUsageMsg struct {
+	path        string
+	free, total uint64
+	ok          bool
+}
+
+// volumeUsageCmd reads the free space off the UI goroutine, as statfs can
+// hang on a network mount.
+func volumeUsageCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		free, total, ok := volumeUsage(path)
+		return volumeUsageMsg{path: path, free: free, total: total, ok: ok}
+	}
+}
+
+func (m model) renderMounts(b *strings.Builder, firstIndex int) {
+	fmt.Fprintf(b, "\n%sFilesystems:%s\n\n", colorGray, colorReset)
+	for i, mount := range m.mounts {
+		idx := firstIndex + i
+		percent := mount.UsedPercent
+		bar := coloredProgressBar(int64(mount.Used), int64(mount.Total), percent)
+		name := padName(trimName(mount.Mount), 28)
+
+		sizeColor := colorGray
+		switch {
+		case percent >= 90:
+			sizeColor = colorRed
+		case percent >= 75:
+			sizeColor = colorYellow
+		}
+
+		entryPrefix := "   "
+		nameSegment := fmt.Sprintf("💽 %s", name)
+		numColor := ""
+		percentColor := ""
+		if idx == m.selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameSegment = fmt.Sprintf("%s💽 %s%s", colorCyan, name, colorReset)
+			numColor = colorCyan
+			percentColor = colorCyan
+			sizeColor = colorCyan
+		}
+
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  %s %s%10s%s  %s%s used · %s free · %s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameSegment, sizeColor, humanizeBytes(int64(mount.Total)), colorReset,
+			colorGray, humanizeBytes(int64(mount.Used)), humanizeBytes(int64(mount.Free)), mount.Fstype, colorReset)
+	}
+}
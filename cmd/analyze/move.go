@@ -0,0 +1,560 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// transferProgress counts what a move or archive has copied so far.
+type transferProgress struct {
+	items int64
+	bytes int64
+}
+
+func (p *transferProgress) add(size int64) {
+	atomic.AddInt64(&p.items, 1)
+	atomic.AddInt64(&p.bytes, size)
+}
+
+func (p *transferProgress) snapshot() (items, bytes int64) {
+	return atomic.LoadInt64(&p.items), atomic.LoadInt64(&p.bytes)
+}
+
+// transferDoneMsg reports a finished, failed or cancelled move or archive.
+type transferDoneMsg struct {
+	kind        string // "move" or "archive"
+	dest        string // Destination directory or archive file
+	targets     []dirEntry
+	moved       []string // Sources that are no longer in place
+	archiveSize int64
+	items       int64
+	bytes       int64
+	cancelled   bool
+	err         error
+}
+
+// actionTargets are the marked entries, or the selection when nothing is marked.
+func (m model) actionTargets() []dirEntry {
+	if len(m.marked) > 0 {
+		targets := make([]dirEntry, 0, len(m.marked))
+		for _, entry := range m.marked {
+			targets = append(targets, entry)
+		}
+		return targets
+	}
+	if m.showLargeFiles {
+		if m.largeSelected < len(m.largeFiles) {
+			file := m.largeFiles[m.largeSelected]
+			return []dirEntry{{Name: file.Name, Path: file.Path, Size: file.Size}}
+		}
+		return nil
+	}
+	if entry, ok := m.selectedEntry(); ok && m.selected < len(m.entries) {
+		return []dirEntry{entry}
+	}
+	return nil
+}
+
+// outermostTargets drops targets inside another target, which travel with it.
+func outermostTargets(targets []dirEntry) []dirEntry {
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Path < targets[j].Path })
+	kept := targets[:0]
+	for _, target := range targets {
+		nested := false
+		for _, outer := range kept {
+			if isPathWithin(target.Path, outer.Path) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			kept = append(kept, target)
+		}
+	}
+	return kept
+}
+
+// startTransferInput asks where to move or archive the targets.
+func (m model) startTransferInput(kind string) (tea.Model, tea.Cmd) {
+	targets := outermostTargets(m.actionTargets())
+	if len(targets) == 0 || m.inOverviewMode() {
+		return m, nil
+	}
+	// Every source lands under its base name, in destDir or at the top of the archive
+	names := make(map[string]string, len(targets))
+	for _, target := range targets {
+		name := filepath.Base(target.Path)
+		if other, ok := names[name]; ok {
+			m.deleteNotice = fmt.Sprintf("Cannot %s %s and %s together, both are named %s", kind, displayPath(other), displayPath(target.Path), name)
+			return m, nil
+		}
+		names[name] = target.Path
+	}
+	var total int64
+	for _, target := range targets {
+		total += target.Size
+	}
+	subject := fmt.Sprintf("%s (%s)", strings.TrimSuffix(targets[0].Name, " →"), humanizeBytes(total))
+	if len(targets) > 1 {
+		subject = fmt.Sprintf("%d entries (%s)", len(targets), humanizeBytes(total))
+	}
+
+	m.transferKind = kind
+	m.transferTargets = targets
+	if kind == "move" {
+		m.transferInput = &textInput{Prompt: fmt.Sprintf("Move %s to directory:", subject), Value: "~/"}
+		return m, nil
+	}
+	name := "mole-archive-" + time.Now().Format("20060102-150405")
+	if len(targets) == 1 {
+		name = strings.TrimSuffix(targets[0].Name, " →")
+	}
+	m.transferInput = &textInput{Prompt: fmt.Sprintf("Archive %s to:", subject), Value: "~/" + name + defaultArchiveExt()}
+	return m, nil
+}
+
+// defaultArchiveExt prefers zstd when the zstd tool is installed.
+func defaultArchiveExt() string {
+	if _, err := exec.LookPath("zstd"); err == nil {
+		return ".tar.zst"
+	}
+	return ".tar.gz"
+}
+
+func (m model) updateTransferInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.transferInput.handleKey(msg)
+	if cancelled {
+		m.transferInput = nil
+		m.transferTargets = nil
+		return m, nil
+	}
+	if !submitted {
+		return m, nil
+	}
+	value := strings.TrimSpace(m.transferInput.Value)
+	m.transferInput = nil
+	dest := expandShortcutPath(value)
+	if dest == "" {
+		m.deleteNotice = fmt.Sprintf("Cannot %s: %q is not an absolute path", m.transferKind, value)
+		m.transferTargets = nil
+		return m, nil
+	}
+
+	sources := make([]string, 0, len(m.transferTargets))
+	for _, target := range m.transferTargets {
+		// Moving the analyzed tree, home or a system root is as bad as deleting it
+		if refused := deleteRefusal(target.Path, m.path); refused != "" && m.transferKind == "move" {
+			m.deleteNotice = fmt.Sprintf("Refusing to move %s: %s", displayPath(target.Path), refused)
+			m.transferTargets = nil
+			return m, nil
+		}
+		if dest == target.Path || isPathWithin(dest, target.Path) {
+			m.deleteNotice = fmt.Sprintf("Cannot %s %s into itself", m.transferKind, displayPath(target.Path))
+			m.transferTargets = nil
+			return m, nil
+		}
+		sources = append(sources, target.Path)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.transferCancel = cancel
+	m.transferProgress = &transferProgress{}
+	if m.transferKind == "move" {
+		m.transferring = "Moving"
+		return m, tea.Batch(movePathsCmd(ctx, m.transferTargets, dest, m.transferProgress), tickCmd())
+	}
+	m.transferring = "Archiving"
+	return m, tea.Batch(archivePathsCmd(ctx, sources, dest, m.transferTargets, m.transferProgress), tickCmd())
+}
+
+// updateTransferringKey lets a running move or archive be stopped or the program quit.
+func (m model) updateTransferringKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		if m.transferCancel != nil {
+			m.transferCancel()
+		}
+		return m, tea.Quit
+	case "esc":
+		if m.transferCancel != nil {
+			m.transferCancel()
+			m.transferCancel = nil
+		}
+	}
+	return m, nil
+}
+
+// handleTransferDone rescans after a move, or offers to delete the originals
+// once an archive is complete.
+func (m model) handleTransferDone(msg transferDoneMsg) (tea.Model, tea.Cmd) {
+	m.transferring = ""
+	m.transferCancel = nil
+	m.transferTargets = nil
+
+	if msg.kind == "archive" {
+		switch {
+		case msg.cancelled:
+			m.deleteNotice = "Archive stopped, nothing was written"
+		case msg.err != nil:
+			m.deleteNotice = fmt.Sprintf("Archive failed: %v", msg.err)
+		default:
+			m.notice = fmt.Sprintf("Archived %s into %s (%s). Press ⌫ to delete the originals",
+				humanizeBytes(msg.bytes), displayPath(msg.dest), humanizeBytes(msg.archiveSize))
+			if len(msg.targets) == 1 {
+				m.deleteTarget = &msg.targets[0]
+			} else {
+				m.deleteBatch = msg.targets
+			}
+			m.deleteConfirm = true
+		}
+		if isPathWithin(msg.dest, m.path) {
+			return m, m.rescanAfterChange([]string{msg.dest})
+		}
+		return m, nil
+	}
+
+	for _, path := range msg.moved {
+		m.removePathFromView(path)
+		delete(m.marked, path)
+	}
+	m.removeSweepPaths(msg.moved)
+	switch {
+	case msg.cancelled:
+		m.deleteNotice = fmt.Sprintf("Move stopped, %d of %d entries moved, the rest is still in place", len(msg.moved), len(msg.targets))
+	case msg.err != nil:
+		m.deleteNotice = fmt.Sprintf("Move failed after %d of %d entries: %v", len(msg.moved), len(msg.targets), msg.err)
+	default:
+		m.notice = fmt.Sprintf("Moved %d entries (%s) to %s", len(msg.moved), humanizeBytes(msg.bytes), displayPath(msg.dest))
+	}
+	changed := append([]string{msg.dest}, msg.moved...)
+	return m, m.rescanAfterChange(changed)
+}
+
+// movePathsCmd moves sources into destDir one by one. Renames are used where
+// possible; across file systems each entry is copied, then the source removed.
+func movePathsCmd(ctx context.Context, targets []dirEntry, destDir string, progress *transferProgress) tea.Cmd {
+	return func() tea.Msg {
+		msg := transferDoneMsg{kind: "move", dest: destDir, targets: targets}
+		var logged deleteResult
+		msg.err = func() error {
+			info, err := os.Stat(destDir)
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", displayPath(destDir))
+			}
+			for _, target := range targets {
+				if ctx.Err() != nil {
+					return nil
+				}
+				src := target.Path
+				items, bytes := progress.snapshot()
+				outcome, err := movePath(ctx, src, filepath.Join(destDir, filepath.Base(src)), target.Size, progress)
+				outcome.Items, outcome.Bytes = progress.snapshot()
+				outcome.Items -= items
+				outcome.Bytes -= bytes
+				if outcome.Removed {
+					msg.moved = append(msg.moved, src)
+				}
+				if outcome.Removed || len(outcome.Failures) > 0 {
+					logged.Outcomes = append(logged.Outcomes, outcome)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		msg.items, msg.bytes = progress.snapshot()
+		msg.cancelled = ctx.Err() != nil
+		_ = logDeletion("move", "tui", logged)
+		return msg
+	}
+}
+
+// movePath moves src to dst. A cancelled or failed copy is removed again so the
+// source stays the only copy.
+// size is the scanned size of src, counted at once when a rename suffices.
+func movePath(ctx context.Context, src, dst string, size int64, progress *transferProgress) (deleteOutcome, error) {
+	outcome := deleteOutcome{Path: src}
+	if _, err := os.Lstat(dst); err == nil {
+		return outcome, fmt.Errorf("%s already exists", displayPath(dst))
+	}
+	err := os.Rename(src, dst)
+	if err == nil {
+		progress.add(size)
+		outcome.Removed = true
+		return outcome, nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return outcome, err
+	}
+
+	if err := copyTree(ctx, src, dst, progress); err != nil || ctx.Err() != nil {
+		_ = os.RemoveAll(dst)
+		return outcome, err
+	}
+	// The copy is complete, so removing the source is not cancellable
+	removal := deletePaths(context.Background(), []string{src}, nil)
+	outcome.Failures = removal.Failures
+	outcome.Removed = len(removal.Removed) == 1
+	if !outcome.Removed {
+		return outcome, fmt.Errorf("copied to %s but could not remove %s", displayPath(dst), displayPath(src))
+	}
+	return outcome, nil
+}
+
+// copyTree copies src to dst keeping modes, modification times and symlinks.
+func copyTree(ctx context.Context, src, dst string, progress *transferProgress) error {
+	type copiedDir struct {
+		path string
+		info fs.FileInfo
+	}
+	var dirs []copiedDir
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			// Writable until its contents are in, the real mode is set afterwards
+			if err := os.Mkdir(target, 0700); err != nil {
+				return err
+			}
+			dirs = append(dirs, copiedDir{path: target, info: info})
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+			progress.add(0)
+			return nil
+		case info.Mode().IsRegular():
+			if err := copyFile(ctx, path, target, info, progress); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s is a special file and cannot be copied", displayPath(path))
+		}
+		if uid, gid, ok := fileOwner(info); ok {
+			_ = os.Lchown(target, int(uid), int(gid))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Deepest first, since filling a directory bumps its modification time
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Chmod(dirs[i].path, dirs[i].info.Mode().Perm())
+		_ = os.Chtimes(dirs[i].path, dirs[i].info.ModTime(), dirs[i].info.ModTime())
+		progress.add(0)
+	}
+	return nil
+}
+
+func copyFile(ctx context.Context, src, dst string, info fs.FileInfo, progress *transferProgress) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, &contextReader{ctx: ctx, r: in, progress: progress}); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	atomic.AddInt64(&progress.items, 1)
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// contextReader stops a copy when ctx is cancelled and counts the bytes read.
+type contextReader struct {
+	ctx      context.Context
+	r        io.Reader
+	progress *transferProgress
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.progress.bytes, int64(n))
+	return n, err
+}
+
+// archivePathsCmd writes sources into a compressed tarball at target. The
+// archive is built next to target and only renamed into place when complete.
+func archivePathsCmd(ctx context.Context, sources []string, target string, targets []dirEntry, progress *transferProgress) tea.Cmd {
+	return func() tea.Msg {
+		msg := transferDoneMsg{kind: "archive", dest: target, targets: targets}
+		msg.archiveSize, msg.err = writeArchive(ctx, sources, target, progress)
+		msg.items, msg.bytes = progress.snapshot()
+		msg.cancelled = ctx.Err() != nil
+		return msg
+	}
+}
+
+func writeArchive(ctx context.Context, sources []string, target string, progress *transferProgress) (int64, error) {
+	if _, err := os.Lstat(target); err == nil {
+		return 0, fmt.Errorf("%s already exists", displayPath(target))
+	}
+	part := target + ".part"
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	fail := func(err error) (int64, error) {
+		file.Close()
+		os.Remove(part)
+		return 0, err
+	}
+
+	var compressed io.WriteCloser
+	var zstd *exec.Cmd
+	switch {
+	case strings.HasSuffix(target, ".tar.zst"), strings.HasSuffix(target, ".tzst"):
+		zstd = exec.CommandContext(ctx, "zstd", "-q", "-T0", "-c")
+		zstd.Stdout = file
+		if compressed, err = zstd.StdinPipe(); err != nil {
+			return fail(err)
+		}
+		if err := zstd.Start(); err != nil {
+			return fail(fmt.Errorf("zstd is not available, use a .tar.gz name instead: %w", err))
+		}
+	case strings.HasSuffix(target, ".tar.gz"), strings.HasSuffix(target, ".tgz"):
+		compressed = gzip.NewWriter(file)
+	default:
+		return fail(errors.New("archive name must end in .tar.zst or .tar.gz"))
+	}
+
+	tw := tar.NewWriter(compressed)
+	var writeErr error
+	for _, src := range sources {
+		if writeErr = addToArchive(ctx, tw, src, progress); writeErr != nil {
+			break
+		}
+	}
+	if err := tw.Close(); writeErr == nil {
+		writeErr = err
+	}
+	if err := compressed.Close(); writeErr == nil {
+		writeErr = err
+	}
+	if zstd != nil {
+		if err := zstd.Wait(); writeErr == nil {
+			writeErr = err
+		}
+	}
+	if writeErr == nil {
+		writeErr = ctx.Err()
+	}
+	if writeErr != nil {
+		return fail(writeErr)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return fail(err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(part)
+		return 0, err
+	}
+	if err := os.Rename(part, target); err != nil {
+		os.Remove(part)
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// addToArchive stores src and everything below it under its base name.
+func addToArchive(ctx context.Context, tw *tar.Writer, src string, progress *transferProgress) error {
+	base := filepath.Dir(src)
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil // Sockets, devices and pipes have no content to keep
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			progress.add(0)
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if _, err := io.Copy(tw, &contextReader{ctx: ctx, r: file, progress: progress}); err != nil {
+			return err
+		}
+		atomic.AddInt64(&progress.items, 1)
+		return nil
+	})
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ownerStat is the usage of one user or group below the scanned path.
+type ownerStat struct {
+	ID    uint32
+	Name  string
+	Size  int64
+	Count int64
+	Dirs  []dirStat // Directories holding most of this owner's bytes, Size counts only theirs
+}
+
+// ownerUsage is the bytes per user and per group in one directory subtree. It
+// flows up the scan so every directory knows how much of it each owner holds.
+type ownerUsage struct {
+	users  map[uint32]int64
+	groups map[uint32]int64
+}
+
+func newOwnerUsage() ownerUsage {
+	return ownerUsage{users: make(map[uint32]int64), groups: make(map[uint32]int64)}
+}
+
+func (u ownerUsage) add(uid, gid uint32, size int64) {
+	u.users[uid] += size
+	u.groups[gid] += size
+}
+
+func (u ownerUsage) merge(src ownerUsage) {
+	for uid, size := range src.users {
+		u.users[uid] += size
+	}
+	for gid, size := range src.groups {
+		u.groups[gid] += size
+	}
+}
+
+func fileOwner(info fs.FileInfo) (uid, gid uint32, ok bool) {
+	if info == nil {
+		return 0, 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return stat.Uid, stat.Gid, true
+}
+
+// pathOwner is the owner of a directory measured with du, whose files are never
+// listed. All of its bytes are attributed to it.
+func pathOwner(path string) (uid, gid uint32, ok bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	return fileOwner(info)
+}
+
+func ownerEntry(owners map[uint32]*ownerStat, id uint32) *ownerStat {
+	stat, ok := owners[id]
+	if !ok {
+		stat = &ownerStat{ID: id}
+		owners[id] = stat
+	}
+	return stat
+}
+
+// addOwnerDirs records path in the directory ranking of every owner with bytes in it.
+func addOwnerDirs(owners map[uint32]*ownerStat, path string, usage map[uint32]int64) {
+	for id, size := range usage {
+		if size > 0 {
+			stat := ownerEntry(owners, id)
+			stat.Dirs = appendTopDirs(stat.Dirs, dirStat{Path: path, Size: size}, dirBySize)
+		}
+	}
+}
+
+func mergeOwners(dst, src map[uint32]*ownerStat) {
+	for id, stat := range src {
+		target := ownerEntry(dst, id)
+		target.Size += stat.Size
+		target.Count += stat.Count
+		for _, dir := range stat.Dirs {
+			target.Dirs = appendTopDirs(target.Dirs, dir, dirBySize)
+		}
+	}
+}
+
+// ownerResult sorts owners by size and resolves their names.
+func ownerResult(owners map[uint32]*ownerStat, lookup func(uint32) string) []ownerStat {
+	out := make([]ownerStat, 0, len(owners))
+	for _, stat := range owners {
+		stat.Name = lookup(stat.ID)
+		stat.Dirs = topDirs(stat.Dirs, dirBySize)
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Size > out[j].Size || (out[i].Size == out[j].Size && out[i].ID < out[j].ID)
+	})
+	return out
+}
+
+func (m model) currentOwners() []ownerStat {
+	if m.insights == nil {
+		return nil
+	}
+	if m.ownersByGroup {
+		return m.insights.Groups
+	}
+	return m.insights.Users
+}
+
+func (m model) currentOwnerDirs() ([]dirStat, bool) {
+	owners := m.currentOwners()
+	if !m.ownerDrill || m.ownerCursor.Selected >= len(owners) {
+		return nil, false
+	}
+	return owners[m.ownerCursor.Selected].Dirs, true
+}
+
+func (m model) updateOwnersKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	owners := m.currentOwners()
+	dirs, drilled := m.currentOwnerDirs()
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.ownerDrill = false
+		return m.refreshCurrentPath()
+	case "esc", "b", "left", "h", "U":
+		if drilled && msg.String() != "U" {
+			m.ownerDrill = false
+			return m, nil
+		}
+		m.showOwners = false
+		m.ownerDrill = false
+		return m, nil
+	case "tab":
+		// Switch between users and groups
+		if !drilled {
+			m.ownersByGroup = !m.ownersByGroup
+			m.ownerCursor = listCursor{}
+		}
+	case "up", "k":
+		if drilled {
+			m.ownerDirCursor.up()
+		} else {
+			m.ownerCursor.up()
+		}
+	case "down", "j":
+		if drilled {
+			m.ownerDirCursor.down(len(dirs), headedViewport(m.height))
+		} else {
+			m.ownerCursor.down(len(owners), headedViewport(m.height))
+		}
+	case "enter", "right", "l":
+		if !drilled {
+			if m.ownerCursor.Selected < len(owners) {
+				m.ownerDrill = true
+				m.ownerDirCursor = listCursor{}
+			}
+		} else if m.ownerDirCursor.Selected < len(dirs) {
+			m.showOwners = false
+			m.ownerDrill = false
+			return m.jumpToDir(dirs[m.ownerDirCursor.Selected].Path)
+		}
+	case "o", "f", "F":
+		if drilled && m.ownerDirCursor.Selected < len(dirs) {
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(dirs[m.ownerDirCursor.Selected].Path)
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderOwners(b *strings.Builder) {
+	owners := m.currentOwners()
+	if m.insights == nil || len(owners) == 0 {
+		fmt.Fprintln(b, "  No ownership data for this scan, press R to rescan")
+		return
+	}
+	kind := "user"
+	if m.ownersByGroup {
+		kind = "group"
+	}
+
+	if dirs, ok := m.currentOwnerDirs(); ok {
+		owner := owners[m.ownerCursor.Selected]
+		fmt.Fprintf(b, "%s👤 Largest directories of %s %s: %s in %s files%s\n\n", colorGray, kind, owner.Name,
+			humanizeBytes(owner.Size), formatNumber(owner.Count), colorReset)
+		if len(dirs) == 0 {
+			fmt.Fprintln(b, "  All of it sits directly in this directory")
+			return
+		}
+		viewport := headedViewport(m.height)
+		start, end := m.ownerDirCursor.window(len(dirs), viewport)
+		for idx := start; idx < end; idx++ {
+			dir := dirs[idx]
+			percent := 0.0
+			if owner.Size > 0 {
+				percent = float64(dir.Size) / float64(owner.Size) * 100
+			}
+			bar := coloredProgressBar(dir.Size, dirs[0].Size, percent)
+			name := padName(truncateMiddle(relativeToScan(m.path, dir.Path), 40), 40)
+
+			entryPrefix := "   "
+			nameColor, numColor, percentColor, sizeColor := "", "", "", colorGray
+			if idx == m.ownerDirCursor.Selected {
+				entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor, numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan, colorCyan
+			}
+			fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  📁 %s%s%s %s%10s%s\n",
+				entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+				nameColor, name, colorReset, sizeColor, humanizeBytes(dir.Size), colorReset)
+		}
+		return
+	}
+
+	var total int64
+	for _, owner := range owners {
+		total += owner.Size
+	}
+	label := "By user"
+	if m.ownersByGroup {
+		label = "By group"
+	}
+	fmt.Fprintf(b, "%s%s  |  %d %ss%s\n\n", colorGray, label, len(owners), kind, colorReset)
+
+	viewport := headedViewport(m.height)
+	start, end := m.ownerCursor.window(len(owners), viewport)
+	for idx := start; idx < end; idx++ {
+		owner := owners[idx]
+		percent := 0.0
+		if total > 0 {
+			percent = float64(owner.Size) / float64(total) * 100
+		}
+		bar := coloredProgressBar(owner.Size, owners[0].Size, percent)
+		name := padName(truncateMiddle(owner.Name, 20), 20)
+
+		entryPrefix := "   "
+		nameSegment := name
+		numColor, percentColor, sizeColor := "", "", colorGray
+		if idx == m.ownerCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameSegment = fmt.Sprintf("%s%s%s", colorCyan, name, colorReset)
+			numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  👤 %s %s%10s%s  %s%s files%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameSegment, sizeColor, humanizeBytes(owner.Size), colorReset,
+			colorGray, formatNumber(owner.Count), colorReset)
+	}
+}
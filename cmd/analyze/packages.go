@@ -0,0 +1,747 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// packageStat is one package version found in dependency directories, with
+// every copy of it below the scanned path.
+type packageStat struct {
+	Ecosystem string // "npm", "pypi", "cargo" or "go"
+	Name      string
+	Version   string // Comma separated versions when grouped by name
+	Size      int64  // All copies together
+	Copies    []packageCopy
+	Versions  int // Distinct versions of Name below the scanned path
+}
+
+type packageCopy struct {
+	Path    string
+	Related []dirStat // Other files of the same copy, such as the downloaded .crate of extracted sources
+	Size    int64     // Path and Related together
+}
+
+// duplicated is the space held by every copy but the largest.
+func (p packageStat) duplicated() int64 {
+	var largest int64
+	for _, c := range p.Copies {
+		largest = max(largest, c.Size)
+	}
+	return p.Size - largest
+}
+
+type packageProgress struct {
+	dirs  int64
+	bytes int64
+}
+
+type packagesMsg struct {
+	root       string
+	packages   []packageStat
+	unreadable *insightCollector
+	err        error
+}
+
+// dependencyEcosystem recognizes the package stores whose contents are
+// grouped by package instead of listed as directories.
+func dependencyEcosystem(path string) string {
+	base := filepath.Base(path)
+	parent := filepath.Base(filepath.Dir(path))
+	switch {
+	case base == "node_modules":
+		return "npm"
+	case base == "site-packages" || base == "dist-packages":
+		return "pypi"
+	case base == "registry" && parent == ".cargo":
+		return "cargo"
+	case base == "mod" && parent == "pkg":
+		return "go"
+	}
+	return ""
+}
+
+// packageCollector merges copies of the same package version from every
+// dependency directory below the root.
+type packageCollector struct {
+	byKey      map[string]*packageStat
+	progress   *packageProgress
+	unreadable *insightCollector // Paths left out of the sizes
+}
+
+func (c *packageCollector) add(ecosystem, name, version, path string, size int64, related ...dirStat) {
+	key := ecosystem + "\x00" + name + "\x00" + version
+	stat := c.byKey[key]
+	if stat == nil {
+		stat = &packageStat{Ecosystem: ecosystem, Name: name, Version: version}
+		c.byKey[key] = stat
+	}
+	stat.Size += size
+	stat.Copies = append(stat.Copies, packageCopy{Path: path, Related: related, Size: size})
+	atomic.AddInt64(&c.progress.bytes, size)
+}
+
+func (c *packageCollector) result() []packageStat {
+	versions := make(map[string]int)
+	for _, stat := range c.byKey {
+		versions[stat.Ecosystem+"\x00"+stat.Name]++
+	}
+	out := make([]packageStat, 0, len(c.byKey))
+	for _, stat := range c.byKey {
+		stat.Versions = versions[stat.Ecosystem+"\x00"+stat.Name]
+		sort.Slice(stat.Copies, func(i, j int) bool { return stat.Copies[i].Size > stat.Copies[j].Size })
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Size > out[j].Size || (out[i].Size == out[j].Size && out[i].Name < out[j].Name)
+	})
+	return out
+}
+
+// findPackages walks root for dependency directories and lists what they hold.
+func findPackages(root string, progress *packageProgress, unreadable *insightCollector) ([]packageStat, error) {
+	collector := &packageCollector{byKey: make(map[string]*packageStat), progress: progress, unreadable: unreadable}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			unreadable.addError(p, err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		atomic.AddInt64(&progress.dirs, 1)
+		switch dependencyEcosystem(p) {
+		case "npm":
+			collectNodeModules(p, collector)
+			return filepath.SkipDir
+		case "pypi":
+			collectSitePackages(p, collector)
+			return filepath.SkipDir
+		case "cargo":
+			collectCargoRegistry(p, collector)
+			return filepath.SkipDir
+		case "go":
+			collectGoModules(p, collector)
+			return filepath.SkipDir
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collector.result(), nil
+}
+
+// collectNodeModules attributes every file to the innermost package holding
+// it, so nested node_modules count towards their own packages. pnpm's
+// .pnpm store is covered too, its links into it are not followed.
+func collectNodeModules(root string, c *packageCollector) {
+	type nodePackage struct {
+		name, version string
+		size          int64
+	}
+	packages := make(map[string]*nodePackage)
+	owner := map[string]string{root: ""}
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			c.unreadable.addError(p, err)
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		parent := filepath.Dir(p)
+		if !d.IsDir() {
+			if pkg := owner[parent]; pkg != "" {
+				if info, err := d.Info(); err == nil {
+					packages[pkg].size += getActualFileSize(p, info)
+				} else {
+					c.unreadable.addError(p, err)
+				}
+			}
+			return nil
+		}
+		atomic.AddInt64(&c.progress.dirs, 1)
+		name := d.Name()
+		slot := filepath.Base(parent) == "node_modules" ||
+			(strings.HasPrefix(filepath.Base(parent), "@") && filepath.Base(filepath.Dir(parent)) == "node_modules")
+		switch {
+		case slot && !strings.HasPrefix(name, "@") && !strings.HasPrefix(name, "."):
+			pkg := &nodePackage{name: name}
+			if strings.HasPrefix(filepath.Base(parent), "@") {
+				pkg.name = filepath.Base(parent) + "/" + name
+			}
+			var manifest struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			}
+			if data, err := os.ReadFile(filepath.Join(p, "package.json")); err == nil && json.Unmarshal(data, &manifest) == nil {
+				if manifest.Name != "" {
+					pkg.name = manifest.Name
+				}
+				pkg.version = manifest.Version
+			}
+			packages[p] = pkg
+			owner[p] = p
+		case name == "node_modules":
+			owner[p] = ""
+		default:
+			owner[p] = owner[parent]
+		}
+		return nil
+	})
+	for path, pkg := range packages {
+		c.add("npm", pkg.name, pkg.version, path, pkg.size)
+	}
+}
+
+// collectSitePackages reads the RECORD of every installed distribution, which
+// lists the files it put into site-packages.
+func collectSitePackages(root string, c *packageCollector) {
+	children, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, child := range children {
+		name := child.Name()
+		if !child.IsDir() || !(strings.HasSuffix(name, ".dist-info") || strings.HasSuffix(name, ".egg-info")) {
+			continue
+		}
+		meta := filepath.Join(root, name)
+		pkgName, version := splitDistName(strings.TrimSuffix(strings.TrimSuffix(name, ".dist-info"), ".egg-info"))
+		var size int64
+		if files, ok := distributionFiles(root, meta); ok {
+			for _, file := range files {
+				if info, err := os.Lstat(file); err == nil && info.Mode().IsRegular() {
+					size += getActualFileSize(file, info)
+				}
+			}
+		} else {
+			size, _ = getDirectoryLogicalSize(meta, c.unreadable)
+			for _, top := range topLevelNames(meta) {
+				for _, candidate := range []string{top, top + ".py"} {
+					if info, err := os.Lstat(filepath.Join(root, candidate)); err == nil {
+						if info.IsDir() {
+							s, _ := getDirectoryLogicalSize(filepath.Join(root, candidate), c.unreadable)
+							size += s
+						} else {
+							size += getActualFileSize(candidate, info)
+						}
+					}
+				}
+			}
+		}
+		c.add("pypi", pkgName, version, meta, size)
+	}
+}
+
+// splitDistName splits "name-1.2.3" and "name-1.2.3-py3.11" at the version.
+func splitDistName(base string) (string, string) {
+	parts := strings.Split(base, "-")
+	if len(parts) < 2 {
+		return base, ""
+	}
+	return strings.ReplaceAll(parts[0], "_", "-"), parts[1]
+}
+
+// distributionFiles lists the files in a RECORD or installed-files.txt that
+// live below root.
+func distributionFiles(root, meta string) ([]string, bool) {
+	var names []string
+	if file, err := os.Open(filepath.Join(meta, "RECORD")); err == nil {
+		reader := csv.NewReader(bufio.NewReader(file))
+		reader.FieldsPerRecord = -1
+		records, _ := reader.ReadAll()
+		file.Close()
+		for _, record := range records {
+			if len(record) > 0 {
+				names = append(names, filepath.Join(root, record[0]))
+			}
+		}
+	} else if data, err := os.ReadFile(filepath.Join(meta, "installed-files.txt")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				names = append(names, filepath.Join(meta, line))
+			}
+		}
+	} else {
+		return nil, false
+	}
+	var files []string
+	for _, name := range names {
+		if isPathWithin(name, root) {
+			files = append(files, name)
+		}
+	}
+	return files, true
+}
+
+func topLevelNames(meta string) []string {
+	data, err := os.ReadFile(filepath.Join(meta, "top_level.txt"))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
+}
+
+// collectCargoRegistry lists the crates of a cargo registry, each copy being
+// the extracted sources together with the downloaded .crate.
+func collectCargoRegistry(root string, c *packageCollector) {
+	for _, crate := range listCargoCrates(root) {
+		size := measurePath(crate.Paths[0], c.unreadable)
+		var related []dirStat
+		for _, path := range crate.Paths[1:] {
+			related = append(related, dirStat{Path: path, Size: measurePath(path, c.unreadable)})
+			size += related[len(related)-1].Size
+		}
+		atomic.AddInt64(&c.progress.dirs, int64(len(crate.Paths)))
+		c.add("cargo", crate.Name, crate.Version, crate.Paths[0], size, related...)
+	}
+}
+
+// cargoCrate is one crate version of one registry index.
+type cargoCrate struct {
+	Name    string
+	Version string
+	Paths   []string // Sources in src/<index> first, then the .crate in cache/<index>
+}
+
+// listCargoCrates pairs each extracted crate in src/<index> of a cargo
+// registry with its downloaded .crate in cache/<index>.
+func listCargoCrates(registry string) []cargoCrate {
+	var crates []cargoCrate
+	byKey := make(map[string]int)
+	for _, area := range []string{"src", "cache"} {
+		indexes, _ := os.ReadDir(filepath.Join(registry, area))
+		for _, index := range indexes {
+			dir := filepath.Join(registry, area, index.Name())
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				base := strings.TrimSuffix(entry.Name(), ".crate")
+				name, version := splitCrateName(base)
+				if version == "" {
+					continue
+				}
+				path := filepath.Join(dir, entry.Name())
+				key := index.Name() + "\x00" + base
+				if i, ok := byKey[key]; ok {
+					crates[i].Paths = append(crates[i].Paths, path)
+					continue
+				}
+				byKey[key] = len(crates)
+				crates = append(crates, cargoCrate{Name: name, Version: version, Paths: []string{path}})
+			}
+		}
+	}
+	return crates
+}
+
+// measurePath is the size of a file or of everything below a directory.
+func measurePath(path string, unreadable *insightCollector) int64 {
+	info, err := os.Lstat(path)
+	if err != nil {
+		unreadable.addError(path, err)
+		return 0
+	}
+	if info.IsDir() {
+		size, _ := getDirectoryLogicalSize(path, unreadable)
+		return size
+	}
+	return getActualFileSize(path, info)
+}
+
+// splitCrateName splits "serde_json-1.0.108" at the dash before the version.
+func splitCrateName(base string) (string, string) {
+	for i := len(base) - 2; i > 0; i-- {
+		if base[i] == '-' && unicode.IsDigit(rune(base[i+1])) {
+			return base[:i], base[i+1:]
+		}
+	}
+	return base, ""
+}
+
+// collectGoModules lists the module@version directories of the module cache,
+// each with its files in the download cache.
+func collectGoModules(root string, c *packageCollector) {
+	for _, module := range listGoModules(root) {
+		size := measurePath(module.Dir, c.unreadable)
+		var related []dirStat
+		for _, path := range module.Downloads {
+			related = append(related, dirStat{Path: path, Size: measurePath(path, c.unreadable)})
+			size += related[len(related)-1].Size
+		}
+		atomic.AddInt64(&c.progress.dirs, 1)
+		c.add("go", module.Path, module.Version, module.Dir, size, related...)
+	}
+}
+
+// goModule is one module@version of the Go module cache.
+type goModule struct {
+	Path      string // Module path with the cache's escaping undone
+	Version   string
+	Dir       string   // Extracted module
+	Downloads []string // cache/download/<module>/@v/<version>.{zip,mod,info,ziphash}
+}
+
+// listGoModules walks the module cache at mod (GOPATH/pkg/mod) for
+// module@version directories, leaving out the download cache itself.
+func listGoModules(mod string) []goModule {
+	var modules []goModule
+	download := filepath.Join(mod, "cache", "download")
+	_ = filepath.WalkDir(mod, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || p == mod {
+			return nil
+		}
+		if p == filepath.Join(mod, "cache") {
+			return filepath.SkipDir
+		}
+		at := strings.LastIndexByte(d.Name(), '@')
+		if at < 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(mod, filepath.Join(filepath.Dir(p), d.Name()[:at]))
+		version := d.Name()[at+1:]
+		downloads, _ := filepath.Glob(filepath.Join(download, rel, "@v", version+".*"))
+		modules = append(modules, goModule{
+			Path:      unescapeModulePath(filepath.ToSlash(rel)),
+			Version:   version,
+			Dir:       p,
+			Downloads: downloads,
+		})
+		return filepath.SkipDir
+	})
+	return modules
+}
+
+// unescapeModulePath undoes the module cache's "!x" escaping of upper case.
+func unescapeModulePath(path string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range path {
+		switch {
+		case r == '!':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// groupPackagesByName folds every version of a package into one row.
+func groupPackagesByName(packages []packageStat) []packageStat {
+	byName := make(map[string]*packageStat)
+	var order []string
+	for _, pkg := range packages {
+		key := pkg.Ecosystem + "\x00" + pkg.Name
+		group := byName[key]
+		if group == nil {
+			group = &packageStat{Ecosystem: pkg.Ecosystem, Name: pkg.Name, Versions: pkg.Versions}
+			byName[key] = group
+			order = append(order, key)
+		}
+		group.Size += pkg.Size
+		group.Copies = append(group.Copies, pkg.Copies...)
+		if group.Version == "" {
+			group.Version = pkg.Version
+		} else {
+			group.Version += ", " + pkg.Version
+		}
+	}
+	out := make([]packageStat, 0, len(order))
+	for _, key := range order {
+		group := byName[key]
+		sort.Slice(group.Copies, func(i, j int) bool { return group.Copies[i].Size > group.Copies[j].Size })
+		out = append(out, *group)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Size > out[j].Size || (out[i].Size == out[j].Size && out[i].Name < out[j].Name)
+	})
+	return out
+}
+
+func (m model) startPackages() (tea.Model, tea.Cmd) {
+	root := m.path
+	progress := &packageProgress{}
+	m.showPackages = true
+	m.packages = nil
+	m.packagesByName = nil
+	m.packagesLoading = true
+	m.packageProgress = progress
+	m.packageCursor = listCursor{}
+	m.packageDrill = false
+	load := func() tea.Msg {
+		unreadable := &insightCollector{}
+		packages, err := findPackages(root, progress, unreadable)
+		return packagesMsg{root: root, packages: packages, unreadable: unreadable, err: err}
+	}
+	return m, tea.Batch(load, tickCmd())
+}
+
+func (m model) currentPackages() []packageStat {
+	if m.packagesGrouped {
+		return m.packagesByName
+	}
+	return m.packages
+}
+
+func (m model) currentPackageCopies() ([]packageCopy, bool) {
+	packages := m.currentPackages()
+	if !m.packageDrill || m.packageCursor.Selected >= len(packages) {
+		return nil, false
+	}
+	return packages[m.packageCursor.Selected].Copies, true
+}
+
+// removePackagePaths drops deleted copies so the view stays accurate until the next load.
+func (m *model) removePackagePaths(paths []string) {
+	if len(m.packages) == 0 || len(paths) == 0 {
+		return
+	}
+	removed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		removed[path] = true
+	}
+	kept := m.packages[:0]
+	for _, pkg := range m.packages {
+		copies := pkg.Copies[:0]
+		pkg.Size = 0
+		for _, c := range pkg.Copies {
+			if !removed[c.Path] {
+				copies = append(copies, c)
+				pkg.Size += c.Size
+			}
+		}
+		pkg.Copies = copies
+		if len(copies) > 0 {
+			kept = append(kept, pkg)
+		}
+	}
+	m.packages = kept
+	m.packagesByName = groupPackagesByName(kept)
+	m.packageCursor.clamp(len(m.currentPackages()), headedViewport(m.height))
+	if copies, ok := m.currentPackageCopies(); ok {
+		m.packageCopyCursor.clamp(len(copies), headedViewport(m.height))
+	} else {
+		m.packageDrill = false
+	}
+}
+
+func (m model) updatePackagesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	packages := m.currentPackages()
+	copies, drilled := m.currentPackageCopies()
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		return m.startPackages()
+	case "esc", "b", "left", "h", "N":
+		if drilled && msg.String() != "N" {
+			m.packageDrill = false
+			return m, nil
+		}
+		m.showPackages = false
+		m.packageDrill = false
+		return m, nil
+	case "tab":
+		// Switch between one row per version and one row per package
+		if !drilled {
+			m.packagesGrouped = !m.packagesGrouped
+			m.packageCursor = listCursor{}
+		}
+	case "up", "k":
+		if drilled {
+			m.packageCopyCursor.up()
+		} else {
+			m.packageCursor.up()
+		}
+	case "down", "j":
+		if drilled {
+			m.packageCopyCursor.down(len(copies), headedViewport(m.height))
+		} else {
+			m.packageCursor.down(len(packages), headedViewport(m.height))
+		}
+	case "enter", "right", "l":
+		if !drilled {
+			if m.packageCursor.Selected < len(packages) {
+				m.packageDrill = true
+				m.packageCopyCursor = listCursor{}
+			}
+		} else if m.packageCopyCursor.Selected < len(copies) {
+			target := copies[m.packageCopyCursor.Selected].Path
+			if info, err := os.Stat(target); err == nil && !info.IsDir() {
+				target = filepath.Dir(target)
+			}
+			m.showPackages = false
+			m.packageDrill = false
+			return m.jumpToDir(target)
+		}
+	case "o", "f", "F":
+		if drilled && m.packageCopyCursor.Selected < len(copies) {
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(copies[m.packageCopyCursor.Selected].Path)
+		}
+	case "delete", "backspace":
+		if drilled && m.packageCopyCursor.Selected < len(copies) {
+			pkg := packages[m.packageCursor.Selected]
+			if pkg.Ecosystem == "pypi" {
+				m.deleteNotice = "Uninstall Python packages with pip, deleting their metadata leaves the files behind"
+				return m, nil
+			}
+			c := copies[m.packageCopyCursor.Selected]
+			m.deleteConfirm = true
+			if len(c.Related) == 0 {
+				m.deleteTarget = &dirEntry{Name: filepath.Base(c.Path), Path: c.Path, Size: c.Size, IsDir: true}
+				return m, nil
+			}
+			// The downloads go too, or the copy would be half removed
+			own := c.Size
+			for _, r := range c.Related {
+				own -= r.Size
+			}
+			m.deleteBatch = []dirEntry{{Name: filepath.Base(c.Path), Path: c.Path, Size: own, IsDir: true}}
+			for _, r := range c.Related {
+				m.deleteBatch = append(m.deleteBatch, dirEntry{Name: filepath.Base(r.Path), Path: r.Path, Size: r.Size})
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderPackages(b *strings.Builder) {
+	if m.packagesLoading {
+		var dirs, bytes int64
+		if m.packageProgress != nil {
+			dirs, bytes = atomic.LoadInt64(&m.packageProgress.dirs), atomic.LoadInt64(&m.packageProgress.bytes)
+		}
+		fmt.Fprintf(b, "%s%s%s%s Reading packages: %s%s dirs%s, %s%s%s\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset,
+			colorYellow, formatNumber(dirs), colorReset,
+			colorGreen, humanizeBytes(bytes), colorReset)
+		return
+	}
+	packages := m.currentPackages()
+	if len(packages) == 0 {
+		fmt.Fprintln(b, "  No node_modules, site-packages, cargo registry or Go module cache below this directory")
+		return
+	}
+
+	if copies, ok := m.currentPackageCopies(); ok {
+		pkg := packages[m.packageCursor.Selected]
+		places := fmt.Sprintf("%d places", len(copies))
+		if len(copies) == 1 {
+			places = "one place"
+		}
+		fmt.Fprintf(b, "%s📦 Copies of %s %s: %s in %s%s\n\n", colorGray, pkg.Name, pkg.Version,
+			humanizeBytes(pkg.Size), places, colorReset)
+		viewport := headedViewport(m.height)
+		start, end := m.packageCopyCursor.window(len(copies), viewport)
+		for idx := start; idx < end; idx++ {
+			c := copies[idx]
+			percent := 0.0
+			if pkg.Size > 0 {
+				percent = float64(c.Size) / float64(pkg.Size) * 100
+			}
+			bar := coloredProgressBar(c.Size, copies[0].Size, percent)
+			name := padName(truncateMiddle(relativeToScan(m.path, c.Path), 50), 50)
+
+			entryPrefix := "   "
+			nameColor, numColor, percentColor, sizeColor := "", "", "", colorGray
+			if idx == m.packageCopyCursor.Selected {
+				entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+				nameColor, numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan, colorCyan
+			}
+			fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  📁 %s%s%s %s%10s%s\n",
+				entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+				nameColor, name, colorReset, sizeColor, humanizeBytes(c.Size), colorReset)
+		}
+		return
+	}
+
+	var total, duplicated int64
+	for _, pkg := range m.packages {
+		total += pkg.Size
+		duplicated += pkg.duplicated()
+	}
+	label := "By version"
+	if m.packagesGrouped {
+		label = "By package"
+	}
+	fmt.Fprintf(b, "%s%s  |  %s packages, %s", colorGray, label, formatNumber(int64(len(packages))), humanizeBytes(total))
+	if duplicated > 0 {
+		fmt.Fprintf(b, "  |  %sDuplicate copies: %s%s", colorYellow, humanizeBytes(duplicated), colorGray)
+	}
+	fmt.Fprintf(b, "%s\n\n", colorReset)
+
+	viewport := headedViewport(m.height)
+	start, end := m.packageCursor.window(len(packages), viewport)
+	for idx := start; idx < end; idx++ {
+		pkg := packages[idx]
+		percent := 0.0
+		if total > 0 {
+			percent = float64(pkg.Size) / float64(total) * 100
+		}
+		bar := coloredProgressBar(pkg.Size, packages[0].Size, percent)
+		title := pkg.Name
+		if !m.packagesGrouped && pkg.Version != "" {
+			title += "@" + pkg.Version
+		}
+		name := padName(truncateMiddle(title, 40), 40)
+
+		var notes []string
+		if m.packagesGrouped {
+			notes = append(notes, fmt.Sprintf("%s%s%s", colorGray, truncateMiddle(pkg.Version, 30), colorReset))
+		}
+		if !m.packagesGrouped && len(pkg.Copies) > 1 {
+			notes = append(notes, fmt.Sprintf("%s×%d copies%s", colorYellow, len(pkg.Copies), colorReset))
+		}
+		if pkg.Versions > 1 {
+			color := colorGray
+			if m.packagesGrouped {
+				color = colorYellow
+			}
+			notes = append(notes, fmt.Sprintf("%s%d versions%s", color, pkg.Versions, colorReset))
+		}
+
+		entryPrefix := "   "
+		nameSegment := name
+		numColor, percentColor, sizeColor := "", "", colorGray
+		if idx == m.packageCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameSegment = fmt.Sprintf("%s%s%s", colorCyan, name, colorReset)
+			numColor, percentColor, sizeColor = colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  📦 %s %s%10s%s  %s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameSegment, sizeColor, humanizeBytes(pkg.Size), colorReset, strings.Join(notes, "  "))
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deletionPlan is a reviewed list of paths to delete later with --apply.
+type deletionPlan struct {
+	Version int         `json:"version"`
+	Created time.Time   `json:"created"`
+	Root    string      `json:"root"` // Deepest directory holding every entry, for the delete guard
+	Entries []planEntry `json:"entries"`
+}
+
+// planEntry fingerprints one path so --apply can tell whether it changed since review.
+type planEntry struct {
+	Path    string    `json:"path"`
+	Dir     bool      `json:"dir,omitempty"`
+	Size    int64     `json:"size"` // Apparent bytes
+	Files   int64     `json:"files"`
+	ModTime time.Time `json:"modTime"` // Latest modification anywhere below Path
+}
+
+const planVersion = 1
+
+type planSavedMsg struct {
+	path  string
+	count int
+	size  int64
+	err   error
+}
+
+// fingerprintPath measures path the same way when the plan is written and
+// when it is applied, so any change below it shows up as a mismatch.
+func fingerprintPath(path string) (planEntry, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return planEntry{}, err
+	}
+	entry := planEntry{Path: path, Dir: info.IsDir(), ModTime: info.ModTime()}
+	if !info.IsDir() {
+		entry.Size = info.Size()
+		entry.Files = 1
+		return entry, nil
+	}
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(entry.ModTime) {
+			entry.ModTime = info.ModTime()
+		}
+		if !d.IsDir() {
+			entry.Files++
+			if info.Mode().IsRegular() {
+				entry.Size += info.Size()
+			}
+		}
+		return nil
+	})
+	return entry, err
+}
+
+// commonDir is the deepest directory containing every path.
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	dir := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for !isPathWithin(path, dir) && dir != filepath.Dir(dir) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// toggleMark adds or removes the selected entry from the plan being built.
+func (m *model) toggleMark() {
+	var entry dirEntry
+	if m.showLargeFiles {
+		if m.largeSelected >= len(m.largeFiles) {
+			return
+		}
+		file := m.largeFiles[m.largeSelected]
+		entry = dirEntry{Name: file.Name, Path: file.Path, Size: file.Size}
+	} else {
+		selected, ok := m.selectedEntry()
+		if !ok || m.selected >= len(m.entries) {
+			return
+		}
+		entry = selected
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]dirEntry)
+	}
+	if _, ok := m.marked[entry.Path]; ok {
+		delete(m.marked, entry.Path)
+	} else {
+		m.marked[entry.Path] = entry
+	}
+}
+
+func (m model) isMarked(path string) bool {
+	_, ok := m.marked[path]
+	return ok
+}
+
+func (m model) markedSize() int64 {
+	var total int64
+	for _, entry := range m.marked {
+		total += entry.Size
+	}
+	return total
+}
+
+func (m model) updatePlanInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.planInput.handleKey(msg)
+	if cancelled {
+		m.planInput = nil
+		return m, nil
+	}
+	if !submitted {
+		return m, nil
+	}
+	value := strings.TrimSpace(m.planInput.Value)
+	m.planInput = nil
+	target := expandShortcutPath(value)
+	if target == "" {
+		var err error
+		if target, err = filepath.Abs(value); err != nil || value == "" {
+			m.deleteNotice = "Cannot save plan: no file name given"
+			return m, nil
+		}
+	}
+	paths := make([]string, 0, len(m.marked))
+	for path := range m.marked {
+		paths = append(paths, path)
+	}
+	m.notice = fmt.Sprintf("Saving plan of %d entries...", len(paths))
+	return m, savePlanCmd(target, paths)
+}
+
+// savePlanCmd fingerprints paths and writes the plan, as a shell script when
+// target ends in .sh and as JSON for --apply otherwise.
+func savePlanCmd(target string, paths []string) tea.Cmd {
+	return func() tea.Msg {
+		plan := deletionPlan{Version: planVersion, Created: time.Now(), Root: commonDir(paths)}
+		var total int64
+		for _, path := range paths {
+			entry, err := fingerprintPath(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // Deleted since it was marked
+			}
+			if err != nil {
+				return planSavedMsg{err: err}
+			}
+			plan.Entries = append(plan.Entries, entry)
+			total += entry.Size
+		}
+		sort.Slice(plan.Entries, func(i, j int) bool { return plan.Entries[i].Size > plan.Entries[j].Size })
+
+		var data []byte
+		mode := os.FileMode(0644)
+		if strings.HasSuffix(target, ".sh") {
+			data = []byte(planScript(plan))
+			mode = 0755
+		} else {
+			var err error
+			if data, err = json.MarshalIndent(plan, "", "  "); err != nil {
+				return planSavedMsg{err: err}
+			}
+			data = append(data, '\n')
+		}
+		if err := os.WriteFile(target, data, mode); err != nil {
+			return planSavedMsg{err: err}
+		}
+		return planSavedMsg{path: target, count: len(plan.Entries), size: total}
+	}
+}
+
+// planScript renders plan as a POSIX shell script with one rm per entry.
+func planScript(plan deletionPlan) string {
+	var b strings.Builder
+	var total int64
+	for _, entry := range plan.Entries {
+		total += entry.Size
+	}
+	fmt.Fprintf(&b, "#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Deletion plan written by mo analyze on %s\n", plan.Created.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "# %d entries under %s, %s in total. Review before running.\n", len(plan.Entries), plan.Root, humanizeBytes(total))
+	fmt.Fprintf(&b, "set -eu\n")
+	for _, entry := range plan.Entries {
+		fmt.Fprintf(&b, "\n# %s, %s files, modified %s\n", humanizeBytes(entry.Size), formatNumber(entry.Files), entry.ModTime.Format("2006-01-02 15:04"))
+		fmt.Fprintf(&b, "rm -rf -- %s\n", shellQuote(entry.Path))
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runApplyPlan deletes the entries of a JSON plan that are unchanged since it
+// was written. Changed or missing entries are reported and left alone.
+func runApplyPlan(out io.Writer, planPath string) error {
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return err
+	}
+	var plan deletionPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return fmt.Errorf("%s is not a JSON plan: %w", planPath, err)
+	}
+	if plan.Version != planVersion {
+		return fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+
+	var ready []string
+	var skipped int
+	for _, entry := range plan.Entries {
+		if _, err := os.Lstat(entry.Path); errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(out, "gone    %s\n", displayPath(entry.Path))
+			continue
+		}
+		if reason := planEntryChange(entry, plan.Root); reason != "" {
+			skipped++
+			fmt.Fprintf(out, "skip    %s  (%s)\n", displayPath(entry.Path), reason)
+			continue
+		}
+		ready = append(ready, entry.Path)
+	}
+
+	result := deletePaths(context.Background(), ready, nil)
+	_ = logDeletion("delete", "plan", result)
+	for _, outcome := range result.Outcomes {
+		status := "deleted"
+		if !outcome.Removed {
+			status = "partial"
+		}
+		fmt.Fprintf(out, "%-7s %s  %s\n", status, displayPath(outcome.Path), humanizeBytes(outcome.Bytes))
+		for _, failure := range outcome.Failures {
+			fmt.Fprintf(out, "        %s: %s\n", displayPath(failure.Path), failure.Reason)
+		}
+	}
+	fmt.Fprintf(out, "\nRemoved %s in %d of %d entries", humanizeBytes(result.Bytes), len(result.Removed), len(plan.Entries))
+	if skipped > 0 {
+		fmt.Fprintf(out, ", %d skipped", skipped)
+	}
+	fmt.Fprintln(out)
+	if skipped > 0 || len(result.Removed) < len(ready) {
+		return errors.New("plan was not applied completely")
+	}
+	return nil
+}
+
+// planEntryChange explains why entry must not be deleted now, or returns "".
+func planEntryChange(entry planEntry, root string) string {
+	if refused := deleteRefusal(entry.Path, root); refused != "" {
+		return "refused, " + refused
+	}
+	current, err := fingerprintPath(entry.Path)
+	switch {
+	case err != nil:
+		return scanErrorReason(err)
+	case current.Dir != entry.Dir:
+		return "type changed"
+	case current.Size != entry.Size:
+		return fmt.Sprintf("size changed from %s to %s", humanizeBytes(entry.Size), humanizeBytes(current.Size))
+	case current.Files != entry.Files:
+		return fmt.Sprintf("file count changed from %s to %s", formatNumber(entry.Files), formatNumber(current.Files))
+	case !current.ModTime.Equal(entry.ModTime):
+		return "modified " + current.ModTime.Format("2006-01-02 15:04")
+	}
+	return ""
+}
+
+// planHint is the footer entry for saving a plan, shown once something is marked.
+func (m model) planHint() string {
+	if len(m.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  |  P Plan(%d)", len(m.marked))
+}
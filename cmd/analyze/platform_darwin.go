@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// fileManagerName is shown in status messages for the reveal action.
+const fileManagerName = "Finder"
+
+// elevatedAccessHint tells how to include paths the scan could not read.
+const elevatedAccessHint = "Grant your terminal Full Disk Access or rerun with sudo to include them"
+
+func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+}
+
+// getChangeTimeFromInfo returns the inode change time (ctime).
+func getChangeTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec)
+}
+
+func openPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "open", path)
+}
+
+func revealPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "open", "-R", path)
+}
+
+// defaultOverviewShortcuts lists the macOS locations shown on the overview screen.
+func defaultOverviewShortcuts(home string) []overviewShortcut {
+	var shortcuts []overviewShortcut
+	if home != "" {
+		shortcuts = append(shortcuts,
+			overviewShortcut{Name: "Home (~)", Path: home},
+			overviewShortcut{Name: "Library (~/Library)", Path: filepath.Join(home, "Library")},
+		)
+	}
+	return append(shortcuts,
+		overviewShortcut{Name: "Applications", Path: "/Applications"},
+		overviewShortcut{Name: "System Library", Path: "/Library"},
+		// Only when it contains real mounted folders (e.g., external disks)
+		overviewShortcut{Name: "Volumes", Path: "/Volumes", MountsOnly: true},
+	)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// fileManagerName is shown in status messages for the reveal action.
+const fileManagerName = "file manager"
+
+// elevatedAccessHint tells how to include paths the scan could not read.
+const elevatedAccessHint = "Rerun with sudo to include them"
+
+func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+}
+
+// getChangeTimeFromInfo returns the inode change time (ctime).
+func getChangeTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+}
+
+func openPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "xdg-open", path)
+}
+
+// revealPathCommand opens the containing directory; xdg-open cannot select a file.
+func revealPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "xdg-open", filepath.Dir(path))
+}
+
+// defaultOverviewShortcuts lists the Linux locations shown on the overview screen.
+// Missing paths are dropped when the overview is built.
+func defaultOverviewShortcuts(home string) []overviewShortcut {
+	var shortcuts []overviewShortcut
+	if home != "" {
+		shortcuts = append(shortcuts,
+			overviewShortcut{Name: "Home (~)", Path: home},
+			overviewShortcut{Name: "Cache (~/.cache)", Path: filepath.Join(home, ".cache")},
+		)
+	}
+	return append(shortcuts,
+		overviewShortcut{Name: "Docker", Path: "/var/lib/docker"},
+		overviewShortcut{Name: "Optional (/opt)", Path: "/opt"},
+		overviewShortcut{Name: "Nix Store", Path: "/nix/store"},
+		overviewShortcut{Name: "Media", Path: "/media", MountsOnly: true},
+		overviewShortcut{Name: "Mounts (/mnt)", Path: "/mnt", MountsOnly: true},
+	)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	//go:embed report.html
+	reportTemplateSource string
+	//go:embed web.tmpl
+	webTemplateSource string
+
+	reportTemplate = template.Must(template.Must(template.New("report").Parse(reportTemplateSource)).Parse(webTemplateSource))
+)
+
+// reportData is everything the HTML report shows. It is embedded in the page
+// as JSON, so the file works offline and can be attached to a ticket.
+type reportData struct {
+	Root      string           `json:"root"`
+	Host      string           `json:"host"`
+	Generated string           `json:"generated"`
+	TotalSize int64            `json:"totalSize"`
+	Tree      reportNode       `json:"tree"`
+	Dirs      []reportDir      `json:"dirs"`
+	Files     []reportFile     `json:"files"`
+	Types     []reportType     `json:"types"`
+	Artifacts []reportArtifact `json:"artifacts"`
+}
+
+type reportNode struct {
+	Name     string       `json:"name"`
+	Size     int64        `json:"size"`
+	Dir      bool         `json:"dir,omitempty"`
+	Children []reportNode `json:"children,omitempty"`
+}
+
+type reportDir struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Own  int64  `json:"own"`
+}
+
+type reportFile struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Category string `json:"category"`
+}
+
+type reportType struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	Count      int64  `json:"count"`
+	Extensions string `json:"extensions"`
+}
+
+type reportArtifact struct {
+	Path         string `json:"path"`
+	Kind         string `json:"kind"`
+	Size         int64  `json:"size"`
+	LastActivity string `json:"lastActivity"`
+}
+
+// runHTMLReport scans root and writes a self-contained HTML report to output.
+func runHTMLReport(output, root string) error {
+	var filesScanned, dirsScanned, bytesScanned int64
+	fmt.Fprintf(os.Stderr, "Scanning %s...\n", displayPath(root))
+	result, err := scanPathConcurrent(root, &filesScanned, &dirsScanned, &bytesScanned, nil)
+	if err != nil {
+		return err
+	}
+	_ = saveCacheToDisk(root, result)
+
+	host, _ := os.Hostname()
+	data := reportData{
+		Root:      root,
+		Host:      host,
+		Generated: time.Now().Format("2006-01-02 15:04"),
+		TotalSize: result.TotalSize,
+		Tree:      reportNode{Name: displayPath(root), Size: result.TotalSize, Dir: true},
+	}
+
+	fmt.Fprintln(os.Stderr, "Expanding the largest directories...")
+	data.Tree.Children = reportChildren(result.Entries, result.TotalSize, reportTreeDepth)
+
+	if insights := result.Insights; insights != nil {
+		for _, dir := range insights.TopDirs {
+			data.Dirs = append(data.Dirs, reportDir{Path: dir.Path, Size: dir.Size, Own: dir.Own})
+		}
+		seen := make(map[string]bool)
+		for _, stat := range insights.Categories {
+			data.Types = append(data.Types, reportType{
+				Name:       stat.Name,
+				Size:       stat.Size,
+				Count:      stat.Count,
+				Extensions: topExtensionsLabel(stat),
+			})
+			for _, file := range stat.Largest {
+				if !seen[file.Path] {
+					seen[file.Path] = true
+					data.Files = append(data.Files, reportFile{Path: file.Path, Size: file.Size, Category: stat.Name})
+				}
+			}
+		}
+		sort.Slice(data.Files, func(i, j int) bool {
+			return data.Files[i].Size > data.Files[j].Size
+		})
+		if len(data.Files) > maxReportFiles {
+			data.Files = data.Files[:maxReportFiles]
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Looking for rebuildable project artifacts...")
+	if items, err := sweepProjects(root, &dirsScanned, &bytesScanned, nil); err == nil {
+		for _, item := range items {
+			activity := ""
+			if !item.LastActivity.IsZero() {
+				activity = formatAge(item.LastActivity)
+			}
+			data.Artifacts = append(data.Artifacts, reportArtifact{
+				Path:         item.Path,
+				Kind:         item.Kind,
+				Size:         item.Size,
+				LastActivity: activity,
+			})
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(output), ".mole-report-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := reportTemplate.Execute(tmp, data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp uses 0600; the report is meant to be shared
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), output); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Report written to %s\n", output)
+	return nil
+}
+
+// reportChildren converts entries to treemap nodes, descending into directories
+// that hold a visible share of the total. Cached scans are reused.
+func reportChildren(entries []dirEntry, total int64, depth int) []reportNode {
+	nodes := make([]reportNode, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Size <= 0 {
+			continue
+		}
+		node := reportNode{Name: strings.TrimSuffix(entry.Name, " →"), Size: entry.Size, Dir: entry.IsDir}
+		if entry.IsDir && depth > 0 && total > 0 && entry.Size*100 >= total {
+			if children, ok := reportLoadEntries(entry.Path); ok {
+				node.Children = reportChildren(children, total, depth-1)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func reportLoadEntries(path string) ([]dirEntry, bool) {
+	if cached, err := loadCacheFromDisk(path); err == nil {
+		return cached.Entries, true
+	}
+	var filesScanned, dirsScanned, bytesScanned int64
+	result, err := scanPathConcurrent(path, &filesScanned, &dirsScanned, &bytesScanned, nil)
+	if err != nil {
+		return nil, false
+	}
+	_ = saveCacheToDisk(path, result)
+	return result.Entries, true
+}
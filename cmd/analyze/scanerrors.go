@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scanError is a path the scan could not read, so its size is missing from the totals.
+type scanError struct {
+	Path   string
+	Reason string
+}
+
+// addError records an unreadable path. Only the first maxScanErrors are kept.
+func (c *insightCollector) addError(path string, err error) {
+	if c == nil || err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errorCount++
+	if len(c.errors) < maxScanErrors {
+		c.errors = append(c.errors, scanError{Path: path, Reason: scanErrorReason(err)})
+	}
+}
+
+// scanErrorReason drops the path that fs errors repeat, leaving e.g. "permission denied".
+func scanErrorReason(err error) string {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// mergeScanErrors adds the paths a view's own walk could not read to those of
+// the directory scan, so the banner and the E list cover them too.
+func (m *model) mergeScanErrors(c *insightCollector) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	errs, count := c.errors, c.errorCount
+	c.mu.Unlock()
+	if count == 0 {
+		return
+	}
+	// Copy, the scan's insights are shared with the cache
+	var insights scanInsights
+	if m.insights != nil {
+		insights = *m.insights
+	}
+	known := make(map[string]bool, len(insights.Errors))
+	for _, e := range insights.Errors {
+		known[e.Path] = true
+	}
+	merged := slices.Clone(insights.Errors)
+	for _, e := range errs {
+		if known[e.Path] {
+			count--
+			continue
+		}
+		if len(merged) < maxScanErrors {
+			merged = append(merged, e)
+		}
+	}
+	insights.Errors = merged
+	insights.ErrorCount += count
+	m.insights = &insights
+}
+
+func (m model) scanErrorCount() int64 {
+	if m.insights == nil {
+		return 0
+	}
+	return m.insights.ErrorCount
+}
+
+// errorBanner warns that totals are understated. It replaces the blank line
+// under the header, so the layout keeps its height.
+func (m model) errorBanner() string {
+	count := m.scanErrorCount()
+	if count == 0 || m.inOverviewMode() {
+		return ""
+	}
+	noun := "paths"
+	if count == 1 {
+		noun = "path"
+	}
+	return fmt.Sprintf("%s⚠ %s %s unreadable, sizes may be incomplete (E to list)%s", colorYellow, formatNumber(count), noun, colorReset)
+}
+
+func (m model) updateErrorsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var errs []scanError
+	if m.insights != nil {
+		errs = m.insights.Errors
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		m.showErrors = false
+		return m.refreshCurrentPath()
+	case "esc", "b", "left", "h", "E":
+		m.showErrors = false
+	case "up", "k":
+		m.errorCursor.up()
+	case "down", "j":
+		m.errorCursor.down(len(errs), headedViewport(m.height))
+	case "f", "F":
+		if m.errorCursor.Selected < len(errs) {
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = revealPathCommand(ctx, path).Run()
+			}(errs[m.errorCursor.Selected].Path)
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderErrors(b *strings.Builder) {
+	if m.scanErrorCount() == 0 {
+		fmt.Fprintln(b, "  Every path was readable")
+		return
+	}
+	errs := m.insights.Errors
+	shown := ""
+	if int64(len(errs)) < m.insights.ErrorCount {
+		shown = fmt.Sprintf(", first %d shown", len(errs))
+	}
+	fmt.Fprintf(b, "%s%s paths could not be read%s. %s%s\n\n", colorGray, formatNumber(m.insights.ErrorCount), shown, elevatedAccessHint, colorReset)
+
+	start, end := m.errorCursor.window(len(errs), headedViewport(m.height))
+	for idx := start; idx < end; idx++ {
+		entry := errs[idx]
+		entryPrefix := "   "
+		nameColor, numColor := "", ""
+		if idx == m.errorCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, numColor = colorCyan, colorCyan
+		}
+		name := padName(truncateMiddle(relativeToScan(m.path, entry.Path), 60), 60)
+		fmt.Fprintf(b, "%s%s%2d.%s ⚠  %s%s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset,
+			nameColor, name, colorReset, colorYellow, entry.Reason, colorReset)
+	}
+}
@@ -31,6 +31,8 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	var total int64
 	entries := make([]dirEntry, 0, len(children))
 	largeFiles := make([]fileEntry, 0, maxLargeFiles*2)
+	insights := newInsightCollector()
+	rootFiles := newFileTally()
 
 	// Use worker pool for concurrent directory scanning
 	// For I/O-bound operations, use more workers than CPU count
@@ -81,10 +83,12 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 			// For symlinks, get their target info but mark them specially
 			info, err := child.Info()
 			if err != nil {
+				insights.addError(fullPath, err)
 				continue
 			}
 			size := getActualFileSize(fullPath, info)
 			atomic.AddInt64(&total, size)
+			rootFiles.addFile(fullPath, size, info)
 
 			entryChan <- dirEntry{
 				Name:       child.Name() + " →",  // Add arrow to indicate symlink
@@ -114,9 +118,10 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 					size, err := getDirectorySizeFromDu(path)
 					if err != nil || size <= 0 {
 						// Fallback to walk if du fails
-						size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
+						size = calculateDirSizeFast(path, insights, filesScanned, dirsScanned, bytesScanned, currentPath)
 					}
 					atomic.AddInt64(&total, size)
+					insights.addFolded(path, size)
 					atomic.AddInt64(dirsScanned, 1)
 
 					entryChan <- dirEntry{
@@ -137,7 +142,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+				size, _ := calculateDirSizeConcurrent(path, largeFileChan, insights, filesScanned, dirsScanned, bytesScanned, currentPath)
 				atomic.AddInt64(&total, size)
 				atomic.AddInt64(dirsScanned, 1)
 
@@ -154,6 +159,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 
 		info, err := child.Info()
 		if err != nil {
+			insights.addError(fullPath, err)
 			continue
 		}
 		// Get actual disk usage for sparse files and cloud files
@@ -161,6 +167,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		atomic.AddInt64(&total, size)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
+		rootFiles.addFile(fullPath, size, info)
 
 		entryChan <- dirEntry{
 			Name:       child.Name(),
@@ -176,6 +183,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	}
 
 	wg.Wait()
+	insights.merge(rootFiles)
 
 	// Close channels and wait for collectors to finish
 	close(entryChan)
@@ -188,6 +196,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	if len(entries) > maxEntries {
 		entries = entries[:maxEntries]
 	}
+	markCleanable(entries)
 
 	// Try to use Spotlight for faster large file discovery
 	if spotlightFiles := findLargeFilesWithSpotlight(root, minLargeFileSize); len(spotlightFiles) > 0 {
@@ -206,6 +215,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		Entries:    entries,
 		LargeFiles: largeFiles,
 		TotalSize:  total,
+		Insights:   insights.result(),
 	}, nil
 }
 
@@ -238,15 +248,15 @@ func shouldSkipFileForLargeTracking(path string) bool {
 	return skipExtensions[ext]
 }
 
-// calculateDirSizeFast performs fast directory size calculation without detailed tracking or large file detection.
-// Updates progress counters in batches to reduce atomic operation overhead.
-func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
+// calculateDirSizeFast walks root for its size only. Unreadable paths are
+// reported to insights, which may be nil.
+func calculateDirSizeFast(root string, insights *insightCollector, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
 	var total int64
 	var localFiles, localDirs int64
 	var batchBytes int64
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), dirSizeTimeout)
 	defer cancel()
 
 	walkFunc := func(path string, d fs.DirEntry, err error) error {
@@ -257,6 +267,7 @@ func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *
 		default:
 		}
 		if err != nil {
+			insights.addError(path, err)
 			return nil
 		}
 		if d.IsDir() {
@@ -270,6 +281,7 @@ func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *
 		}
 		info, err := d.Info()
 		if err != nil {
+			insights.addError(path, err)
 			return nil
 		}
 		// Get actual disk usage for sparse files and cloud files
@@ -290,7 +302,9 @@ func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *
 		return nil
 	}
 
-	_ = filepath.WalkDir(root, walkFunc)
+	if err := filepath.WalkDir(root, walkFunc); err == context.DeadlineExceeded {
+		insights.addError(root, fmt.Errorf("gave up after %v, size is partial", dirSizeTimeout))
+	}
 
 	// Final update for remaining counts
 	if localFiles > 0 {
@@ -384,15 +398,22 @@ func isInFoldedDir(path string) bool {
 	return false
 }
 
-func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
+// calculateDirSizeConcurrent returns the size of root and how its bytes split
+// between owners, so the caller can rank its own directory per owner.
+func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, insights *insightCollector, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) (int64, ownerUsage) {
 	// Read immediate children
 	children, err := os.ReadDir(root)
 	if err != nil {
-		return 0
+		insights.addError(root, err)
+		return 0, ownerUsage{}
 	}
 
-	var total int64
+	var total, largestChild int64
 	var wg sync.WaitGroup
+	tally := newFileTally()
+	// Filled by subdirectory goroutines while this loop tallies files
+	var childOwnersMu sync.Mutex
+	childOwners := newOwnerUsage()
 
 	// Limit concurrent subdirectory scans to avoid too many goroutines
 	maxConcurrent := runtime.NumCPU() * 2
@@ -409,12 +430,14 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 			// For symlinks, just count their size without following
 			info, err := child.Info()
 			if err != nil {
+				insights.addError(fullPath, err)
 				continue
 			}
 			size := getActualFileSize(fullPath, info)
 			total += size
 			atomic.AddInt64(filesScanned, 1)
 			atomic.AddInt64(bytesScanned, size)
+			tally.addFile(fullPath, size, info)
 			continue
 		}
 
@@ -426,10 +449,22 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 				go func(path string) {
 					defer wg.Done()
 					size, err := getDirectorySizeFromDu(path)
-					if err == nil && size > 0 {
-						atomic.AddInt64(&total, size)
+					if err != nil || size <= 0 {
+						// du stops at unreadable subdirectories, the walk names them
+						size = calculateDirSizeFast(path, insights, filesScanned, dirsScanned, bytesScanned, currentPath)
+					} else {
 						atomic.AddInt64(bytesScanned, size)
+					}
+					if size > 0 {
+						atomic.AddInt64(&total, size)
 						atomic.AddInt64(dirsScanned, 1)
+						storeMaxInt64(&largestChild, size)
+						insights.addFolded(path, size)
+						if uid, gid, ok := pathOwner(path); ok {
+							childOwnersMu.Lock()
+							childOwners.add(uid, gid, size)
+							childOwnersMu.Unlock()
+						}
 					}
 				}(fullPath)
 				continue
@@ -442,9 +477,13 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+				size, owners := calculateDirSizeConcurrent(path, largeFileChan, insights, filesScanned, dirsScanned, bytesScanned, currentPath)
 				atomic.AddInt64(&total, size)
 				atomic.AddInt64(dirsScanned, 1)
+				storeMaxInt64(&largestChild, size)
+				childOwnersMu.Lock()
+				childOwners.merge(owners)
+				childOwnersMu.Unlock()
 			}(fullPath)
 			continue
 		}
@@ -452,6 +491,7 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 		// Handle files
 		info, err := child.Info()
 		if err != nil {
+			insights.addError(fullPath, err)
 			continue
 		}
 
@@ -459,6 +499,7 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 		total += size
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
+		tally.addFile(fullPath, size, info)
 
 		// Track large files
 		if !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
@@ -472,7 +513,20 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 	}
 
 	wg.Wait()
-	return total
+	tally.owners.merge(childOwners)
+	tally.addDir(root, total, largestChild)
+	insights.merge(tally)
+	return total, tally.owners
+}
+
+// storeMaxInt64 atomically raises *addr to value if value is larger.
+func storeMaxInt64(addr *int64, value int64) {
+	for {
+		current := atomic.LoadInt64(addr)
+		if value <= current || atomic.CompareAndSwapInt64(addr, current, value) {
+			return
+		}
+	}
 }
 
 // measureOverviewSize calculates the size of a directory using multiple strategies.
@@ -499,7 +553,7 @@ func measureOverviewSize(path string) (int64, error) {
 		return duSize, nil
 	}
 
-	if logicalSize, err := getDirectoryLogicalSize(path); err == nil && logicalSize > 0 {
+	if logicalSize, err := getDirectoryLogicalSize(path, nil); err == nil && logicalSize > 0 {
 		_ = storeOverviewSize(path, logicalSize)
 		return logicalSize, nil
 	}
@@ -544,13 +598,13 @@ func getDirectorySizeFromDu(path string) (int64, error) {
 	return kb * 1024, nil
 }
 
-func getDirectoryLogicalSize(path string) (int64, error) {
+// getDirectoryLogicalSize sums the file sizes below path. Unreadable paths
+// are reported to insights, which may be nil, and left out of the total.
+func getDirectoryLogicalSize(path string, insights *insightCollector) (int64, error) {
 	var total int64
 	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
-			if os.IsPermission(err) {
-				return filepath.SkipDir
-			}
+			insights.addError(p, err)
 			return nil
 		}
 		if d.IsDir() {
@@ -558,6 +612,7 @@ func getDirectoryLogicalSize(path string) (int64, error) {
 		}
 		info, err := d.Info()
 		if err != nil {
+			insights.addError(p, err)
 			return nil
 		}
 		total += getActualFileSize(p, info)
@@ -589,11 +644,3 @@ func getLastAccessTime(path string) time.Time {
 	}
 	return getLastAccessTimeFromInfo(info)
 }
-
-func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
-		return time.Time{}
-	}
-	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
-}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	//go:embed serve.html
+	serveTemplateSource string
+
+	serveTemplate = template.Must(template.Must(template.New("serve").Parse(serveTemplateSource)).Parse(webTemplateSource))
+)
+
+// webServer exposes the scanner and cache over a small JSON API for the
+// embedded browser front end. Browsing and deletion stay inside root.
+type webServer struct {
+	root  string
+	token string // Required on mutating requests, handed out by the index page
+}
+
+type apiEntry struct {
+	Name       string     `json:"name"`
+	Path       string     `json:"path"`
+	Size       int64      `json:"size"`
+	Dir        bool       `json:"dir,omitempty"`
+	Cleanable  bool       `json:"cleanable,omitempty"`
+	LastAccess int64      `json:"lastAccess,omitempty"` // Unix seconds
+	Children   []apiEntry `json:"children,omitempty"`   // Only when already cached
+}
+
+type apiScan struct {
+	Root       string     `json:"root"`
+	Path       string     `json:"path"`
+	Parent     string     `json:"parent,omitempty"`
+	TotalSize  int64      `json:"totalSize"`
+	Entries    []apiEntry `json:"entries"`
+	LargeFiles []apiEntry `json:"largeFiles"`
+}
+
+type apiDeleteRequest struct {
+	Path    string `json:"path"`
+	Confirm string `json:"confirm"` // Must repeat the base name of Path
+}
+
+type apiDeleteResponse struct {
+	Path    string `json:"path"`
+	Removed int64  `json:"removed"` // Files and directories removed
+}
+
+// runWebServer serves root on addr until the process is stopped. Only loopback
+// addresses are accepted, since the API can delete files.
+func runWebServer(addr, root string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("refusing to serve on %s, use a loopback address such as 127.0.0.1", addr)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	s := &webServer{root: root, token: hex.EncodeToString(token)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /api/scan", s.handleScan)
+	mux.HandleFunc("POST /api/delete", s.handleDelete)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.guard(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s (Ctrl+C to stop)\n", displayPath(root), addr)
+	return server.ListenAndServe()
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// guard rejects requests addressed to other host names, which blocks DNS
+// rebinding, and requires the page token on anything that changes state.
+func (s *webServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !isLoopbackHost(strings.Trim(host, "[]")) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Mole-Token")), []byte(s.token)) != 1 {
+				http.Error(w, "missing or invalid token", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *webServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = serveTemplate.Execute(w, struct{ Root, Token string }{s.root, s.token})
+}
+
+// resolvePath turns a query path into a clean absolute path inside root.
+func (s *webServer) resolvePath(raw string) (string, error) {
+	if raw == "" {
+		return s.root, nil
+	}
+	path := filepath.Clean(raw)
+	if !filepath.IsAbs(path) {
+		return "", errors.New("path must be absolute")
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside %s", path, s.root)
+	}
+	return path, nil
+}
+
+func (s *webServer) handleScan(w http.ResponseWriter, r *http.Request) {
+	path, err := s.resolvePath(r.URL.Query().Get("path"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	if r.URL.Query().Get("fresh") == "1" {
+		invalidateCache(path)
+	}
+	result, err := s.scan(path)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := apiScan{Root: s.root, Path: path, TotalSize: result.TotalSize}
+	if path != s.root {
+		resp.Parent = filepath.Dir(path)
+	}
+	for _, entry := range result.Entries {
+		item := apiEntryFromDir(entry)
+		if entry.IsDir {
+			// Nest cached children so the treemap can show a second level for free
+			if cached, err := loadCacheFromDisk(entry.Path); err == nil {
+				for _, child := range cached.Entries {
+					item.Children = append(item.Children, apiEntryFromDir(child))
+				}
+			}
+		}
+		resp.Entries = append(resp.Entries, item)
+	}
+	for _, file := range result.LargeFiles {
+		resp.LargeFiles = append(resp.LargeFiles, apiEntry{Name: file.Name, Path: file.Path, Size: file.Size})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// scan returns the cached result for path, scanning and caching it when needed.
+func (s *webServer) scan(path string) (scanResult, error) {
+	if cached, err := loadCacheFromDisk(path); err == nil {
+		return scanResult{
+			Entries:    cached.Entries,
+			LargeFiles: cached.LargeFiles,
+			TotalSize:  cached.TotalSize,
+			Insights:   cached.Insights,
+		}, nil
+	}
+	var filesScanned, dirsScanned, bytesScanned int64
+	v, err, _ := scanGroup.Do(path, func() (interface{}, error) {
+		return scanPathConcurrent(path, &filesScanned, &dirsScanned, &bytesScanned, nil)
+	})
+	if err != nil {
+		return scanResult{}, err
+	}
+	result := v.(scanResult)
+	_ = saveCacheToDisk(path, result)
+	return result, nil
+}
+
+func (s *webServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	var req apiDeleteRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	path, err := s.resolvePath(req.Path)
+	if err != nil || req.Path == "" {
+		writeJSONError(w, http.StatusBadRequest, errors.New("path must be inside the served directory"))
+		return
+	}
+	if refused := deleteRefusal(path, s.root); refused != "" {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("refusing to delete %s: %s", displayPath(path), refused))
+		return
+	}
+	if req.Confirm != filepath.Base(path) {
+		writeJSONError(w, http.StatusBadRequest, errors.New("confirmation does not match the name"))
+		return
+	}
+
+	// Not r.Context(): a closed tab must not leave the tree half deleted
+	result := deletePaths(context.Background(), []string{path}, nil)
+	_ = logDeletion("delete", "web", result)
+	// Every cached level from the parent up to root now has stale sizes
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		invalidateCache(dir)
+		if dir == s.root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	invalidateCache(path)
+	if result.FailureCount > 0 {
+		first := result.Failures[0]
+		writeJSONError(w, http.StatusInternalServerError, fmt.Errorf("%s paths could not be deleted, first %s: %s",
+			formatNumber(result.FailureCount), first.Path, first.Reason))
+		return
+	}
+	writeJSON(w, http.StatusOK, apiDeleteResponse{Path: path, Removed: result.Items})
+}
+
+func apiEntryFromDir(entry dirEntry) apiEntry {
+	item := apiEntry{
+		Name:      strings.TrimSuffix(entry.Name, " →"),
+		Path:      entry.Path,
+		Size:      entry.Size,
+		Dir:       entry.IsDir,
+		Cleanable: entry.Cleanable,
+	}
+	if !entry.LastAccess.IsZero() {
+		item.LastAccess = entry.LastAccess.Unix()
+	}
+	return item
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
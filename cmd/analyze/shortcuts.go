@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type overviewShortcut struct {
+	Name       string
+	Path       string
+	MountsOnly bool // Only shown when the directory holds real mounted volumes
+}
+
+// shortcutConfig mirrors ~/.config/mole/analyze_shortcuts: one path per line adds
+// a shortcut, a leading "-" hides a default. Defaults keep tracking new releases
+// while user changes persist.
+type shortcutConfig struct {
+	Added  []string
+	Hidden map[string]bool
+}
+
+func getShortcutConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "mole", shortcutConfigFile), nil
+}
+
+func loadShortcutConfig() shortcutConfig {
+	cfg := shortcutConfig{Hidden: make(map[string]bool)}
+	configPath, err := getShortcutConfigPath()
+	if err != nil {
+		return cfg
+	}
+	file, err := os.Open(configPath)
+	if err != nil {
+		return cfg
+	}
+	defer file.Close()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hide := strings.HasPrefix(line, "-")
+		path := expandShortcutPath(strings.TrimSpace(strings.TrimPrefix(line, "-")))
+		if path == "" {
+			continue
+		}
+		if hide {
+			cfg.Hidden[path] = true
+			continue
+		}
+		if !seen[path] {
+			seen[path] = true
+			cfg.Added = append(cfg.Added, path)
+		}
+	}
+	return cfg
+}
+
+func saveShortcutConfig(cfg shortcutConfig) error {
+	configPath, err := getShortcutConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# Mole analyzer overview shortcuts\n")
+	b.WriteString("# One directory per line adds a shortcut, \"-/path\" hides a default.\n")
+	for _, path := range cfg.Added {
+		fmt.Fprintln(&b, collapseHomePath(path))
+	}
+	for _, path := range slices.Sorted(maps.Keys(cfg.Hidden)) {
+		fmt.Fprintf(&b, "-%s\n", collapseHomePath(path))
+	}
+
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, configPath)
+}
+
+// addOverviewShortcut pins path to the overview, un-hiding it if it is a default.
+func addOverviewShortcut(path string) error {
+	path = expandShortcutPath(path)
+	if path == "" {
+		return fmt.Errorf("path must be absolute or start with ~")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", displayPath(path))
+	}
+
+	cfg := loadShortcutConfig()
+	delete(cfg.Hidden, path)
+	isDefault := false
+	for _, shortcut := range defaultOverviewShortcuts(os.Getenv("HOME")) {
+		if shortcut.Path == path {
+			isDefault = true
+			break
+		}
+	}
+	if !isDefault && !slices.Contains(cfg.Added, path) {
+		cfg.Added = append(cfg.Added, path)
+	}
+	return saveShortcutConfig(cfg)
+}
+
+// removeOverviewShortcut drops a user shortcut or hides a default one.
+func removeOverviewShortcut(path string) error {
+	cfg := loadShortcutConfig()
+	kept := cfg.Added[:0]
+	removed := false
+	for _, added := range cfg.Added {
+		if added == path {
+			removed = true
+			continue
+		}
+		kept = append(kept, added)
+	}
+	cfg.Added = kept
+	if !removed {
+		cfg.Hidden[path] = true
+	}
+	return saveShortcutConfig(cfg)
+}
+
+func createOverviewEntries() []dirEntry {
+	home := os.Getenv("HOME")
+	cfg := loadShortcutConfig()
+	entries := []dirEntry{}
+	seen := make(map[string]bool)
+
+	for _, shortcut := range defaultOverviewShortcuts(home) {
+		if cfg.Hidden[shortcut.Path] || seen[shortcut.Path] {
+			continue
+		}
+		if shortcut.MountsOnly {
+			if !hasUsefulVolumeMounts(shortcut.Path) {
+				continue
+			}
+		} else if !isExistingDir(shortcut.Path) {
+			continue
+		}
+		seen[shortcut.Path] = true
+		entries = append(entries, dirEntry{Name: shortcut.Name, Path: shortcut.Path, IsDir: true, Size: -1})
+	}
+
+	for _, path := range cfg.Added {
+		if seen[path] || !isExistingDir(path) {
+			continue
+		}
+		seen[path] = true
+		entries = append(entries, dirEntry{Name: displayPath(path), Path: path, IsDir: true, Size: -1})
+	}
+
+	return entries
+}
+
+func hasUsefulVolumeMounts(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skip hidden control entries for Spotlight/TimeMachine etc.
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		info, err := os.Lstat(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			continue // Ignore the synthetic MacintoshHD link
+		}
+		if info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func isExistingDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// expandShortcutPath resolves "~" and cleans the path; relative paths are rejected.
+func expandShortcutPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	if !filepath.IsAbs(path) {
+		return ""
+	}
+	return filepath.Clean(path)
+}
+
+func collapseHomePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// projectKind recognises a project by its manifest files. Which children are
+// artifacts is decided by the cleanable rules, so user rules apply here too.
+type projectKind struct {
+	Name    string
+	Markers []string
+}
+
+var projectKinds = []projectKind{
+	{Name: "Node", Markers: []string{"package.json"}},
+	{Name: "Rust", Markers: []string{"Cargo.toml"}},
+	{Name: "Maven", Markers: []string{"pom.xml"}},
+	{Name: "Gradle", Markers: []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}},
+	{Name: "Python", Markers: []string{"pyproject.toml", "setup.py", "requirements.txt", "Pipfile"}},
+	{Name: "Go", Markers: []string{"go.mod"}},
+	{Name: "Ruby", Markers: []string{"Gemfile"}},
+	{Name: "PHP", Markers: []string{"composer.json"}},
+	{Name: "Swift", Markers: []string{"Package.swift"}},
+	{Name: "CocoaPods", Markers: []string{"Podfile"}},
+	{Name: "Dart", Markers: []string{"pubspec.yaml"}},
+	{Name: "Elixir", Markers: []string{"mix.exs"}},
+}
+
+// sweepAgeFilters are the "untouched for" thresholds cycled by the select key.
+var sweepAgeFilters = []struct {
+	Label string
+	Age   time.Duration
+}{
+	{"3mo", 90 * 24 * time.Hour},
+	{"6mo", 180 * 24 * time.Hour},
+	{"1yr", 365 * 24 * time.Hour},
+}
+
+type sweepItem struct {
+	Project      string
+	Kind         string
+	Path         string
+	Name         string
+	Size         int64
+	LastActivity time.Time
+	Marked       bool
+}
+
+type sweepResultMsg struct {
+	root  string
+	items []sweepItem
+	err   error
+}
+
+func sweepCmd(root string, dirsScanned, bytesScanned *int64, currentPath *string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := sweepProjects(root, dirsScanned, bytesScanned, currentPath)
+		return sweepResultMsg{root: root, items: items, err: err}
+	}
+}
+
+// sweepProjects walks root, finds project directories by their manifest files
+// and measures every rebuildable artifact directory inside them.
+func sweepProjects(root string, dirsScanned, bytesScanned *int64, currentPath *string) ([]sweepItem, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var items []sweepItem
+	artifactPaths := make(map[string]bool)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (strings.HasPrefix(name, ".") || foldDirs[name]) {
+			return filepath.SkipDir
+		}
+		// Artifacts are measured later; walking them costs the most I/O and
+		// turns up "projects" inside build output such as target/debug/build
+		if artifactPaths[path] {
+			return filepath.SkipDir
+		}
+		atomic.AddInt64(dirsScanned, 1)
+		if currentPath != nil {
+			*currentPath = path
+		}
+
+		children, err := os.ReadDir(path)
+		if err != nil {
+			return filepath.SkipDir
+		}
+		kinds, artifacts := detectProjectArtifacts(path, children)
+		if len(artifacts) == 0 {
+			return nil
+		}
+		lastActivity := projectLastActivity(path, children, artifacts)
+		for _, artifact := range sortedArtifactNames(artifacts) {
+			artifactPaths[filepath.Join(path, artifact)] = true
+			items = append(items, sweepItem{
+				Project:      path,
+				Kind:         strings.Join(kinds, "/"),
+				Path:         filepath.Join(path, artifact),
+				Name:         artifact,
+				LastActivity: lastActivity,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	measureSweepItems(items, bytesScanned)
+
+	kept := items[:0]
+	for _, item := range items {
+		if item.Size > 0 {
+			kept = append(kept, item)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].Size > kept[j].Size
+	})
+	return kept, nil
+}
+
+// detectProjectArtifacts matches a directory listing against projectKinds and,
+// for project directories, returns the children the cleanable rules accept.
+func detectProjectArtifacts(dir string, children []fs.DirEntry) ([]string, map[string]bool) {
+	files := make(map[string]bool, len(children))
+	for _, child := range children {
+		if !child.IsDir() {
+			files[child.Name()] = true
+		}
+	}
+
+	var kinds []string
+	for _, kind := range projectKinds {
+		for _, marker := range kind.Markers {
+			if files[marker] {
+				kinds = append(kinds, kind.Name)
+				break
+			}
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	artifacts := make(map[string]bool)
+	for _, child := range children {
+		if child.IsDir() && isCleanableDir(filepath.Join(dir, child.Name())) {
+			artifacts[child.Name()] = true
+		}
+	}
+	return kinds, artifacts
+}
+
+// projectLastActivity approximates when a project was last worked on: the newest
+// mtime among its non-artifact top-level entries and the git index/HEAD log.
+func projectLastActivity(project string, children []fs.DirEntry, artifacts map[string]bool) time.Time {
+	var latest time.Time
+	consider := func(info fs.FileInfo) {
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	for _, child := range children {
+		if artifacts[child.Name()] || child.Name() == ".git" || child.Name() == ".DS_Store" {
+			continue
+		}
+		if info, err := child.Info(); err == nil {
+			consider(info)
+		}
+	}
+	for _, name := range []string{"index", "HEAD", filepath.Join("logs", "HEAD")} {
+		if info, err := os.Stat(filepath.Join(project, ".git", name)); err == nil {
+			consider(info)
+		}
+	}
+	return latest
+}
+
+func sortedArtifactNames(artifacts map[string]bool) []string {
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func measureSweepItems(items []sweepItem, bytesScanned *int64) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxDirWorkers)
+	var files, dirs int64
+	for i := range items {
+		wg.Add(1)
+		go func(item *sweepItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			size, err := getDirectorySizeFromDu(item.Path)
+			if err != nil || size <= 0 {
+				size = calculateDirSizeFast(item.Path, nil, &files, &dirs, bytesScanned, nil)
+			} else {
+				atomic.AddInt64(bytesScanned, size)
+			}
+			item.Size = size
+		}(&items[i])
+	}
+	wg.Wait()
+}
+
+func (m model) startSweep() (tea.Model, tea.Cmd) {
+	m.sweepItems = nil
+	m.status = fmt.Sprintf("Finding projects under %s...", displayPath(m.path))
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	return m, tea.Batch(sweepCmd(m.path, m.dirsScanned, m.bytesScanned, m.currentPath), tickCmd())
+}
+
+func (m model) updateSweepKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "C", "b", "left", "h":
+		m.showSweep = false
+		return m, nil
+	case "up", "k":
+		m.sweepCursor.up()
+	case "down", "j":
+		m.sweepCursor.down(len(m.sweepItems), calculateViewport(m.height, true))
+	case " ":
+		if len(m.sweepItems) > 0 {
+			m.sweepItems[m.sweepCursor.Selected].Marked = !m.sweepItems[m.sweepCursor.Selected].Marked
+		}
+	case "s":
+		// Cycle "untouched for" thresholds; marks replace the current selection
+		m.sweepAgeFilter = (m.sweepAgeFilter + 1) % (len(sweepAgeFilters) + 1)
+		count := 0
+		for i := range m.sweepItems {
+			item := &m.sweepItems[i]
+			item.Marked = false
+			if m.sweepAgeFilter == 0 {
+				continue
+			}
+			cutoff := sweepAgeFilters[m.sweepAgeFilter-1].Age
+			if !item.LastActivity.IsZero() && time.Since(item.LastActivity) >= cutoff {
+				item.Marked = true
+				count++
+			}
+		}
+		if m.sweepAgeFilter == 0 {
+			m.status = "Selection cleared"
+		} else {
+			m.status = fmt.Sprintf("Selected %d artifacts idle %s+", count, sweepAgeFilters[m.sweepAgeFilter-1].Label)
+		}
+	case "o", "f", "F":
+		if len(m.sweepItems) > 0 {
+			item := m.sweepItems[m.sweepCursor.Selected]
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(item.Path)
+		}
+	case "delete", "backspace":
+		var batch []dirEntry
+		for _, item := range m.sweepItems {
+			if item.Marked {
+				batch = append(batch, dirEntry{Name: item.Name, Path: item.Path, Size: item.Size, IsDir: true})
+			}
+		}
+		if len(batch) == 0 && len(m.sweepItems) > 0 {
+			item := m.sweepItems[m.sweepCursor.Selected]
+			batch = append(batch, dirEntry{Name: item.Name, Path: item.Path, Size: item.Size, IsDir: true})
+		}
+		if len(batch) > 0 {
+			m.deleteConfirm = true
+			m.deleteBatch = batch
+		}
+	}
+	return m, nil
+}
+
+// removeSweepPaths drops deleted artifacts from the sweep list.
+func (m *model) removeSweepPaths(paths []string) {
+	removed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		removed[path] = true
+	}
+	kept := m.sweepItems[:0]
+	for _, item := range m.sweepItems {
+		if !removed[item.Path] {
+			kept = append(kept, item)
+		}
+	}
+	m.sweepItems = kept
+	m.sweepCursor.clamp(len(m.sweepItems), calculateViewport(m.height, true))
+}
+
+func (m model) sweepTotals() (total, marked int64, markedCount int, projects int) {
+	seen := make(map[string]bool)
+	for _, item := range m.sweepItems {
+		total += item.Size
+		if !seen[item.Project] {
+			seen[item.Project] = true
+			projects++
+		}
+		if item.Marked {
+			marked += item.Size
+			markedCount++
+		}
+	}
+	return
+}
+
+func (m model) renderSweep(b *strings.Builder) {
+	if len(m.sweepItems) == 0 {
+		fmt.Fprintln(b, "  No rebuildable project artifacts found")
+		return
+	}
+
+	maxSize := int64(1)
+	for _, item := range m.sweepItems {
+		if item.Size > maxSize {
+			maxSize = item.Size
+		}
+	}
+	start, end := m.sweepCursor.window(len(m.sweepItems), calculateViewport(m.height, true))
+
+	for idx := start; idx < end; idx++ {
+		item := m.sweepItems[idx]
+		label := truncateMiddle(displayPath(item.Path), 35)
+		paddedLabel := padName(label, 35)
+		check := "☐"
+		if item.Marked {
+			check = colorYellow + "☑" + colorReset
+		}
+		entryPrefix := "   "
+		nameColor := ""
+		sizeColor := colorGray
+		numColor := ""
+		if idx == m.sweepCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor = colorCyan
+			sizeColor = colorCyan
+			numColor = colorCyan
+		}
+		activity := "unknown"
+		if !item.LastActivity.IsZero() {
+			activity = formatAge(item.LastActivity)
+		}
+		bar := coloredProgressBar(item.Size, maxSize, 0)
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s  |  🧹 %s%s%s  %s%10s%s  %s%s · %s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, check, bar,
+			nameColor, paddedLabel, colorReset,
+			sizeColor, humanizeBytes(item.Size), colorReset,
+			colorGray, item.Kind, activity, colorReset)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dirStat describes one directory found anywhere below the scanned path.
+// Own excludes the largest subdirectory, so a parent does not rank high
+// merely because it holds one huge child.
+type dirStat struct {
+	Path   string
+	Size   int64
+	Own    int64
+	Folded bool // Measured with du, subdirectories unknown
+}
+
+// Ties go to the shorter path, so a parent lists before a child of equal size.
+func dirBySize(a, b dirStat) bool {
+	return a.Size > b.Size || (a.Size == b.Size && a.Path < b.Path)
+}
+
+func dirByOwn(a, b dirStat) bool {
+	return a.Own > b.Own || (a.Own == b.Own && a.Path < b.Path)
+}
+
+// appendTopDirs keeps roughly the top maxTopDirs entries, trimming lazily.
+func appendTopDirs(dirs []dirStat, dir dirStat, less func(a, b dirStat) bool) []dirStat {
+	dirs = append(dirs, dir)
+	if len(dirs) >= maxTopDirs*2 {
+		dirs = topDirs(dirs, less)
+	}
+	return dirs
+}
+
+func topDirs(dirs []dirStat, less func(a, b dirStat) bool) []dirStat {
+	sort.Slice(dirs, func(i, j int) bool {
+		return less(dirs[i], dirs[j])
+	})
+	if len(dirs) > maxTopDirs {
+		dirs = dirs[:maxTopDirs]
+	}
+	return dirs
+}
+
+func (m model) currentTopDirs() []dirStat {
+	if m.insights == nil {
+		return nil
+	}
+	if m.topDirsByOwn {
+		return m.insights.HeavyDirs
+	}
+	return m.insights.TopDirs
+}
+
+func (m model) updateTopDirsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	dirs := m.currentTopDirs()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "r":
+		return m.refreshCurrentPath()
+	case "esc", "b", "left", "h", "D":
+		m.showTopDirs = false
+		return m, nil
+	case "tab":
+		// Rank by own size instead of total size, or back
+		m.topDirsByOwn = !m.topDirsByOwn
+		m.topDirCursor = listCursor{}
+	case "up", "k":
+		m.topDirCursor.up()
+	case "down", "j":
+		m.topDirCursor.down(len(dirs), headedViewport(m.height))
+	case "enter", "right", "l":
+		if m.topDirCursor.Selected < len(dirs) {
+			m.showTopDirs = false
+			return m.jumpToDir(dirs[m.topDirCursor.Selected].Path)
+		}
+	case "delete", "backspace":
+		if m.topDirCursor.Selected < len(dirs) {
+			dir := dirs[m.topDirCursor.Selected]
+			m.deleteConfirm = true
+			m.deleteTarget = &dirEntry{Name: filepath.Base(dir.Path), Path: dir.Path, Size: dir.Size, IsDir: true}
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderTopDirs(b *strings.Builder) {
+	dirs := m.currentTopDirs()
+	if len(dirs) == 0 {
+		fmt.Fprintln(b, "  No directory data for this scan, press R to rescan")
+		return
+	}
+	ranking := "total size"
+	if m.topDirsByOwn {
+		ranking = "own size, without their largest subdirectory"
+	}
+	fmt.Fprintf(b, "%sLargest directories at any depth, by %s%s\n\n", colorGray, ranking, colorReset)
+
+	rank := func(dir dirStat) int64 {
+		if m.topDirsByOwn {
+			return dir.Own
+		}
+		return dir.Size
+	}
+	maxSize := rank(dirs[0])
+	viewport := headedViewport(m.height)
+	start, end := m.topDirCursor.window(len(dirs), viewport)
+	for idx := start; idx < end; idx++ {
+		dir := dirs[idx]
+		percent := 0.0
+		if m.totalSize > 0 {
+			percent = float64(rank(dir)) / float64(m.totalSize) * 100
+		}
+		bar := coloredProgressBar(rank(dir), maxSize, percent)
+		label := padName(truncateMiddle(relativeToScan(m.path, dir.Path), 35), 35)
+
+		hint := fmt.Sprintf("own %s", humanizeBytes(dir.Own))
+		if m.topDirsByOwn {
+			hint = fmt.Sprintf("total %s", humanizeBytes(dir.Size))
+		}
+		if dir.Folded {
+			hint = "folded"
+		}
+
+		entryPrefix := "   "
+		nameColor, sizeColor, numColor, percentColor := "", colorGray, "", ""
+		if idx == m.topDirCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, sizeColor, numColor, percentColor = colorCyan, colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  📁 %s%s%s  %s%10s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			nameColor, label, colorReset, sizeColor, humanizeBytes(rank(dir)), colorReset,
+			colorGray, hint, colorReset)
+	}
+}
+
+// relativeToScan shows path relative to the scanned root, which keeps deep rows readable.
+func relativeToScan(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return displayPath(path)
+}
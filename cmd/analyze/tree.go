@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// treeRow is one visible line of the tree layout.
+type treeRow struct {
+	Entry    dirEntry
+	Depth    int
+	Expanded bool
+	Loading  bool
+	Err      error // Why the children could not be listed
+}
+
+type treeChildrenMsg struct {
+	path   string
+	result scanResult
+	err    error
+}
+
+// treeChildrenCmd loads one subtree for expansion, preferring the disk cache.
+// The result lands in the in-memory cache, so entering the directory later is instant.
+func treeChildrenCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		if cached, err := loadCacheFromDisk(path); err == nil {
+			return treeChildrenMsg{path: path, result: scanResult{
+				Entries:    cached.Entries,
+				LargeFiles: cached.LargeFiles,
+				TotalSize:  cached.TotalSize,
+				Insights:   cached.Insights,
+			}}
+		}
+
+		var filesScanned, dirsScanned, bytesScanned int64
+		v, err, _ := scanGroup.Do(path, func() (interface{}, error) {
+			return scanPathConcurrent(path, &filesScanned, &dirsScanned, &bytesScanned, nil)
+		})
+		if err != nil {
+			return treeChildrenMsg{path: path, err: err}
+		}
+		result := v.(scanResult)
+		go func(p string, r scanResult) {
+			_ = saveCacheToDisk(p, r)
+		}(path, result)
+		return treeChildrenMsg{path: path, result: result}
+	}
+}
+
+// treeChildren returns the known children of path, or false if it still needs loading.
+func (m model) treeChildren(path string) ([]dirEntry, bool) {
+	if path == m.path {
+		return m.entries, true
+	}
+	cached, ok := m.cache[path]
+	if !ok || cached.Dirty {
+		return nil, false
+	}
+	return cached.Entries, true
+}
+
+// treeRows flattens the expanded part of the hierarchy in display order.
+func (m model) treeRows() []treeRow {
+	var rows []treeRow
+	var walk func(entries []dirEntry, depth int)
+	walk = func(entries []dirEntry, depth int) {
+		for _, entry := range entries {
+			row := treeRow{Entry: entry, Depth: depth}
+			if entry.IsDir && m.treeExpanded[entry.Path] {
+				row.Expanded = true
+				children, ok := m.treeChildren(entry.Path)
+				row.Err = m.treeErrors[entry.Path]
+				row.Loading = !ok && row.Err == nil
+				rows = append(rows, row)
+				if ok {
+					walk(children, depth+1)
+				}
+				continue
+			}
+			rows = append(rows, row)
+		}
+	}
+	walk(m.entries, 0)
+	return rows
+}
+
+// expandTreeDir marks path expanded and loads its children when they are not cached.
+func (m *model) expandTreeDir(path string) tea.Cmd {
+	if m.treeExpanded == nil {
+		m.treeExpanded = make(map[string]bool)
+	}
+	m.treeExpanded[path] = true
+	delete(m.treeErrors, path)
+	if _, ok := m.treeChildren(path); ok {
+		return nil
+	}
+	return m.loadTreeDir(path)
+}
+
+func (m *model) loadTreeDir(path string) tea.Cmd {
+	if m.treeLoading == nil {
+		m.treeLoading = make(map[string]bool)
+	}
+	if m.treeLoading[path] {
+		return nil
+	}
+	m.treeLoading[path] = true
+	return treeChildrenCmd(path)
+}
+
+// reloadExpandedTree refetches expanded subtrees whose cached children went stale.
+func (m *model) reloadExpandedTree() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, row := range m.treeRows() {
+		if row.Loading {
+			if cmd := m.loadTreeDir(row.Entry.Path); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) updateTreeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.treeRows()
+	var row treeRow
+	if m.treeCursor.Selected < len(rows) {
+		row = rows[m.treeCursor.Selected]
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "v":
+		m.showTree = false
+		return m, nil
+	case "r":
+		return m.refreshCurrentPath()
+	case "up", "k":
+		m.treeCursor.up()
+	case "down", "j":
+		m.treeCursor.down(len(rows), calculateViewport(m.height, false))
+	case "right", "l":
+		if row.Entry.IsDir && !row.Expanded {
+			return m, m.expandTreeDir(row.Entry.Path)
+		}
+	case "enter", " ":
+		if !row.Entry.IsDir {
+			return m, nil
+		}
+		if row.Expanded {
+			delete(m.treeExpanded, row.Entry.Path)
+			return m, nil
+		}
+		return m, m.expandTreeDir(row.Entry.Path)
+	case "left", "h":
+		if row.Expanded {
+			delete(m.treeExpanded, row.Entry.Path)
+			return m, nil
+		}
+		// Move to the parent row
+		for i := m.treeCursor.Selected - 1; i >= 0; i-- {
+			if rows[i].Depth < row.Depth {
+				m.treeCursor.Selected = i
+				m.treeCursor.clamp(len(rows), calculateViewport(m.height, false))
+				break
+			}
+		}
+	case "o", "f", "F":
+		if row.Entry.Path != "" {
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(row.Entry.Path)
+		}
+	case "delete", "backspace":
+		if row.Entry.Path != "" {
+			entry := row.Entry
+			m.deleteConfirm = true
+			m.deleteTarget = &entry
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderTree(b *strings.Builder) {
+	rows := m.treeRows()
+	if len(rows) == 0 {
+		fmt.Fprintln(b, "  Empty directory")
+		return
+	}
+
+	var maxSize int64 = 1
+	for _, entry := range m.entries {
+		if entry.Size > maxSize {
+			maxSize = entry.Size
+		}
+	}
+	start, end := m.treeCursor.window(len(rows), calculateViewport(m.height, false))
+	for idx := start; idx < end; idx++ {
+		row := rows[idx]
+		entry := row.Entry
+		size := entry.Size
+		if size < 0 {
+			size = 0
+		}
+		percent := 0.0
+		if m.totalSize > 0 {
+			percent = float64(size) / float64(m.totalSize) * 100
+		}
+		bar := coloredProgressBar(size, maxSize, percent)
+
+		marker := "  "
+		icon := "📄"
+		if entry.IsDir {
+			marker, icon = "▸ ", "📁"
+			if row.Expanded {
+				marker = "▾ "
+			}
+		}
+		indent := strings.Repeat("  ", row.Depth)
+		nameWidth := 34 - len(indent)
+		if nameWidth < 8 {
+			nameWidth = 8
+		}
+		name := padName(truncateMiddle(entry.Name, nameWidth), nameWidth)
+
+		hint := ""
+		if row.Loading {
+			hint = "scanning..."
+		} else if row.Err != nil {
+			hint = colorRed + "cannot read: " + row.Err.Error()
+		}
+
+		entryPrefix := "   "
+		nameColor, sizeColor, numColor, percentColor := "", colorGray, "", ""
+		if idx == m.treeCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, sizeColor, numColor, percentColor = colorCyan, colorCyan, colorCyan, colorCyan
+		}
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s%5.1f%%%s  |  %s%s%s %s%s%s  %s%10s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, bar, percentColor, percent, colorReset,
+			indent, marker, icon, nameColor, name, colorReset,
+			sizeColor, humanizeBytes(size), colorReset, colorGray, hint, colorReset)
+	}
+}
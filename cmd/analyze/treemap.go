@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treemapRect is an area in layout units. Terminal cells are about twice as
+// tall as wide, so the layout works in half rows to keep tiles square-ish.
+type treemapRect struct {
+	X, Y, W, H float64
+}
+
+// treemapTile is a laid out rectangle in terminal cells.
+type treemapTile struct {
+	Index          int // Position in the entry list it was laid out from
+	X0, Y0, X1, Y1 int // Half-open cell bounds
+}
+
+func (t treemapTile) empty() bool {
+	return t.X1 <= t.X0 || t.Y1 <= t.Y0
+}
+
+// treemapPalette pairs a base color with an alternate shade for nested tiles.
+var treemapPalette = [][2]string{
+	{"#2F5D8A", "#3B6F9F"},
+	{"#6A3F8A", "#7B4F9C"},
+	{"#2F7A5A", "#3B8C6A"},
+	{"#8A5A2F", "#9C6B3B"},
+	{"#8A2F4F", "#9C3B5E"},
+	{"#5A7A2F", "#6B8C3B"},
+	{"#2F7A85", "#3B8C97"},
+	{"#7A6A2F", "#8C7B3B"},
+}
+
+var treemapSelectedColors = [2]string{"#5FAFD7", "#87CEEB"}
+
+// squarify lays out values, sorted largest first, inside r with tiles as close
+// to square as the squarified treemap algorithm gets them.
+func squarify(values []float64, r treemapRect) []treemapRect {
+	out := make([]treemapRect, 0, len(values))
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	if total <= 0 || r.W <= 0 || r.H <= 0 {
+		return append(out, make([]treemapRect, len(values))...)
+	}
+
+	scale := r.W * r.H / total
+	areas := make([]float64, len(values))
+	for i, v := range values {
+		areas[i] = v * scale
+	}
+
+	for len(areas) > 0 {
+		side := math.Min(r.W, r.H)
+		count := 1
+		for count < len(areas) && worstRatio(areas[:count+1], side) <= worstRatio(areas[:count], side) {
+			count++
+		}
+		row := areas[:count]
+		var rowSum float64
+		for _, a := range row {
+			rowSum += a
+		}
+		if r.W >= r.H {
+			// Column along the left edge
+			w := rowSum / r.H
+			y := r.Y
+			for _, a := range row {
+				h := a / w
+				out = append(out, treemapRect{X: r.X, Y: y, W: w, H: h})
+				y += h
+			}
+			r.X += w
+			r.W -= w
+		} else {
+			// Row along the top edge
+			h := rowSum / r.W
+			x := r.X
+			for _, a := range row {
+				w := a / h
+				out = append(out, treemapRect{X: x, Y: r.Y, W: w, H: h})
+				x += w
+			}
+			r.Y += h
+			r.H -= h
+		}
+		areas = areas[count:]
+	}
+	return out
+}
+
+// worstRatio is the worst aspect ratio of a row of areas laid along side.
+func worstRatio(row []float64, side float64) float64 {
+	var sum, largest float64
+	smallest := math.MaxFloat64
+	for _, a := range row {
+		sum += a
+		largest = math.Max(largest, a)
+		smallest = math.Min(smallest, a)
+	}
+	if sum <= 0 || smallest <= 0 {
+		return math.MaxFloat64
+	}
+	s2, side2 := sum*sum, side*side
+	return math.Max(side2*largest/s2, s2/(side2*smallest))
+}
+
+// layoutTreemap tiles entries into a width x height cell area. Rounding shared
+// edges the same way keeps neighbouring tiles gapless.
+func layoutTreemap(entries []dirEntry, x0, y0, width, height int) []treemapTile {
+	var values []float64
+	var indexes []int
+	for i, entry := range entries {
+		if entry.Size > 0 {
+			values = append(values, float64(entry.Size))
+			indexes = append(indexes, i)
+		}
+	}
+	area := treemapRect{X: float64(x0), Y: float64(y0) * 2, W: float64(width), H: float64(height) * 2}
+	tiles := make([]treemapTile, 0, len(values))
+	for i, r := range squarify(values, area) {
+		tiles = append(tiles, treemapTile{
+			Index: indexes[i],
+			X0:    int(math.Round(r.X)),
+			Y0:    int(math.Round(r.Y / 2)),
+			X1:    int(math.Round(r.X + r.W)),
+			Y1:    int(math.Round((r.Y + r.H) / 2)),
+		})
+	}
+	return tiles
+}
+
+func (m model) treemapSize() (width, height int) {
+	width = m.width - 2
+	if width <= 0 {
+		width = 78
+	}
+	height = calculateViewport(m.height, false) - 1
+	if height < 4 {
+		height = 4
+	}
+	return width, height
+}
+
+func (m model) treemapTiles() []treemapTile {
+	width, height := m.treemapSize()
+	return layoutTreemap(m.entries, 0, 0, width, height)
+}
+
+// loadTreemapChildren fetches children of tiles big enough to show a second level.
+func (m *model) loadTreemapChildren() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tile := range m.treemapTiles() {
+		entry := m.entries[tile.Index]
+		if !entry.IsDir || tile.X1-tile.X0 < 8 || tile.Y1-tile.Y0 < 4 {
+			continue
+		}
+		if _, ok := m.treeChildren(entry.Path); ok {
+			continue
+		}
+		if cmd := m.loadTreeDir(entry.Path); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateTreemapKey moves the selection between tiles with the arrow keys. Other
+// keys fall through to the list handlers, which act on the same selection.
+func (m model) updateTreemapKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	var dx, dy int
+	switch msg.String() {
+	case "esc", "M":
+		m.showTreemap = false
+		return m, nil, true
+	case "left", "h":
+		dx = -1
+	case "right", "l":
+		dx = 1
+	case "up", "k":
+		dy = -1
+	case "down", "j":
+		dy = 1
+	default:
+		return m, nil, false
+	}
+
+	tiles := m.treemapTiles()
+	var current treemapTile
+	found := false
+	for _, tile := range tiles {
+		if tile.Index == m.selected && !tile.empty() {
+			current, found = tile, true
+			break
+		}
+	}
+	if !found {
+		if len(tiles) > 0 {
+			m.selected = tiles[0].Index
+		}
+		return m, nil, true
+	}
+
+	// Pick the nearest tile whose center lies in the pressed direction
+	cx, cy := float64(current.X0+current.X1)/2, float64(current.Y0+current.Y1)/2
+	best, bestScore := -1, math.MaxFloat64
+	for _, tile := range tiles {
+		if tile.Index == current.Index || tile.empty() {
+			continue
+		}
+		tx, ty := float64(tile.X0+tile.X1)/2, float64(tile.Y0+tile.Y1)/2
+		along := (tx-cx)*float64(dx) + (ty-cy)*float64(dy)*2
+		if along <= 0 {
+			continue
+		}
+		across := math.Abs((tx-cx)*float64(dy)) + math.Abs((ty-cy)*float64(dx))*2
+		if score := along + across*2; score < bestScore {
+			best, bestScore = tile.Index, score
+		}
+	}
+	if best >= 0 {
+		m.selected = best
+	}
+	return m, nil, true
+}
+
+func (m model) renderTreemap(b *strings.Builder) {
+	width, height := m.treemapSize()
+	tiles := layoutTreemap(m.entries, 0, 0, width, height)
+	if len(tiles) == 0 {
+		fmt.Fprintln(b, "  Empty directory")
+		return
+	}
+
+	styles := []lipgloss.Style{lipgloss.NewStyle()}
+	styleIndex := make(map[string]int)
+	styleFor := func(background string, selected bool) int {
+		key := fmt.Sprintf("%s|%t", background, selected)
+		if idx, ok := styleIndex[key]; ok {
+			return idx
+		}
+		style := lipgloss.NewStyle().Background(lipgloss.Color(background)).Foreground(lipgloss.Color("#F0F0F0"))
+		if selected {
+			style = style.Foreground(lipgloss.Color("#000000")).Bold(true)
+		}
+		styles = append(styles, style)
+		styleIndex[key] = len(styles) - 1
+		return len(styles) - 1
+	}
+
+	cells := make([][]string, height)
+	cellStyles := make([][]int, height)
+	for y := range cells {
+		cells[y] = make([]string, width)
+		cellStyles[y] = make([]int, width)
+		for x := range cells[y] {
+			cells[y][x] = " "
+		}
+	}
+	fill := func(tile treemapTile, style int) {
+		for y := tile.Y0; y < tile.Y1 && y < height; y++ {
+			for x := tile.X0; x < tile.X1 && x < width; x++ {
+				cellStyles[y][x] = style
+			}
+		}
+	}
+	label := func(x, y, maxWidth int, text string) {
+		if y >= height || maxWidth <= 0 {
+			return
+		}
+		text = truncateMiddle(text, maxWidth)
+		for _, r := range text {
+			w := runeWidth(r)
+			if x+w > width || maxWidth < w {
+				return
+			}
+			cells[y][x] = string(r)
+			if w == 2 {
+				cells[y][x+1] = ""
+			}
+			x += w
+			maxWidth -= w
+		}
+	}
+
+	for n, tile := range tiles {
+		if tile.empty() {
+			continue
+		}
+		entry := m.entries[tile.Index]
+		colors := treemapPalette[n%len(treemapPalette)]
+		selected := tile.Index == m.selected
+		if selected {
+			colors = treemapSelectedColors
+		}
+		fill(tile, styleFor(colors[0], selected))
+
+		tileWidth, tileHeight := tile.X1-tile.X0, tile.Y1-tile.Y0
+		label(tile.X0+1, tile.Y0, tileWidth-2, entry.Name)
+		labelRows := 1
+		if tileHeight >= 2 {
+			label(tile.X0+1, tile.Y0+1, tileWidth-2, humanizeBytes(entry.Size))
+			labelRows = 2
+		}
+
+		// Second level from cached children, enough to show nested structure
+		if !entry.IsDir || tileWidth < 8 || tileHeight < labelRows+2 {
+			continue
+		}
+		children, ok := m.treeChildren(entry.Path)
+		if !ok {
+			continue
+		}
+		inner := layoutTreemap(children, tile.X0+1, tile.Y0+labelRows, tileWidth-2, tileHeight-labelRows-1)
+		for i, child := range inner {
+			if child.empty() {
+				continue
+			}
+			fill(child, styleFor(colors[(i+1)%2], selected))
+			if child.X1-child.X0 >= 6 {
+				label(child.X0, child.Y0, child.X1-child.X0-1, children[child.Index].Name)
+			}
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		var line strings.Builder
+		line.WriteString(" ")
+		runStart := 0
+		for x := 1; x <= width; x++ {
+			if x < width && cellStyles[y][x] == cellStyles[y][runStart] {
+				continue
+			}
+			text := strings.Join(cells[y][runStart:x], "")
+			line.WriteString(styles[cellStyles[y][runStart]].Render(text))
+			runStart = x
+		}
+		fmt.Fprintln(b, line.String())
+	}
+
+	if m.selected < len(m.entries) {
+		entry := m.entries[m.selected]
+		percent := 0.0
+		if m.totalSize > 0 {
+			percent = float64(entry.Size) / float64(m.totalSize) * 100
+		}
+		fmt.Fprintf(b, "%s▶ %s  %s  %.1f%%%s\n", colorCyan, entry.Name, humanizeBytes(entry.Size), percent, colorReset)
+	}
+}
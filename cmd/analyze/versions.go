@@ -0,0 +1,603 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// versionItem is one installed version of an SDK or artifact in a toolchain
+// cache. Old versions start marked for removal; the latest and pinned ones do not.
+type versionItem struct {
+	Group   string // "Node", "Gradle", "Maven org.slf4j:slf4j-api", ...
+	Root    string // Cache directory the group was found in
+	Version string
+	Paths   []dirStat // Everything belonging to this version, e.g. a crate's source and download
+	Size    int64
+	Note    string // "latest", "pinned (nvm default)" or "" for an old version
+	Marked  bool
+}
+
+type versionsMsg struct {
+	root       string
+	items      []versionItem
+	unreadable *insightCollector
+}
+
+// versionGroup collects the versions of one SDK or artifact while a cache is read.
+type versionGroup struct {
+	name     string
+	root     string
+	versions map[string]*versionItem
+	pinned   func(version string) string // Why a version is pinned, "" if it is not
+}
+
+type versionCollector struct {
+	dir        string // Only versions inside it are measured and listed
+	groups     map[string]*versionGroup
+	order      []string
+	bytes      *int64
+	unreadable *insightCollector // Paths left out of the sizes
+}
+
+func (c *versionCollector) group(name, root string) *versionGroup {
+	group := c.groups[name]
+	if group == nil {
+		group = &versionGroup{name: name, root: root, versions: make(map[string]*versionItem)}
+		c.groups[name] = group
+		c.order = append(c.order, name)
+	}
+	return group
+}
+
+// add measures path and files it under version of group.
+func (c *versionCollector) add(group *versionGroup, version, path string) {
+	if !isPathWithin(path, c.dir) {
+		return
+	}
+	var size int64
+	if info, err := os.Lstat(path); err != nil {
+		return
+	} else if info.IsDir() {
+		size, _ = getDirectoryLogicalSize(path, c.unreadable)
+	} else {
+		size = getActualFileSize(path, info)
+	}
+	atomic.AddInt64(c.bytes, size)
+	item := group.versions[version]
+	if item == nil {
+		item = &versionItem{Group: group.name, Root: group.root, Version: version}
+		group.versions[version] = item
+	}
+	item.Paths = append(item.Paths, dirStat{Path: path, Size: size})
+	item.Size += size
+}
+
+// findVersionedCaches reads the toolchain caches at, in or above dir. Only
+// versions inside dir are listed, as nothing outside it may be deleted.
+func findVersionedCaches(dir string, unreadable *insightCollector, bytes *int64, currentPath *string) []versionItem {
+	c := &versionCollector{dir: dir, groups: make(map[string]*versionGroup), bytes: bytes, unreadable: unreadable}
+	readers := map[string]func(*versionCollector, string){
+		".nvm":    readNvm,
+		".pyenv":  readPyenv,
+		".rustup": readRustup,
+		".gradle": readGradle,
+		".m2":     readMaven,
+		".cargo":  readCargoVersions,
+		"go":      readGoModVersions,
+	}
+	for _, name := range versionedCaches {
+		root := findCacheRoot(dir, name)
+		if root == "" {
+			continue
+		}
+		if currentPath != nil {
+			*currentPath = root
+		}
+		readers[name](c, root)
+	}
+
+	var items []versionItem
+	for _, name := range c.order {
+		group := c.groups[name]
+		var versions []*versionItem
+		for _, item := range group.versions {
+			versions = append(versions, item)
+		}
+		if len(versions) < 2 {
+			continue
+		}
+		sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i].Version, versions[j].Version) > 0 })
+		latest := true
+		for _, item := range versions {
+			switch {
+			case group.pinned != nil && group.pinned(item.Version) != "":
+				item.Note = group.pinned(item.Version)
+			case latest && versionNumbers(item.Version) != nil:
+				item.Note = "latest"
+				latest = false
+			case versionNumbers(item.Version) == nil:
+				item.Note = "channel"
+			}
+			if versionNumbers(item.Version) != nil {
+				latest = false
+			}
+			item.Marked = item.Note == ""
+			items = append(items, *item)
+		}
+	}
+	// Groups with the most to reclaim first, versions newest first within them
+	reclaim := make(map[string]int64)
+	for _, item := range items {
+		if item.Marked {
+			reclaim[item.Group] += item.Size
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Group != items[j].Group {
+			if reclaim[items[i].Group] != reclaim[items[j].Group] {
+				return reclaim[items[i].Group] > reclaim[items[j].Group]
+			}
+			return items[i].Group < items[j].Group
+		}
+		return false
+	})
+	return items
+}
+
+// findCacheRoot returns the cache called name that is dir, holds dir or sits
+// directly in dir.
+func findCacheRoot(dir, name string) string {
+	for d := dir; d != filepath.Dir(d); d = filepath.Dir(d) {
+		if filepath.Base(d) == name {
+			return d
+		}
+	}
+	candidate := filepath.Join(dir, name)
+	if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+		return candidate
+	}
+	return ""
+}
+
+var versionedCaches = []string{".nvm", ".pyenv", ".rustup", ".gradle", ".m2", ".cargo", "go"}
+
+// versionsHint offers S only where a toolchain cache is in view or above it.
+func (m model) versionsHint() string {
+	for _, name := range versionedCaches {
+		found := false
+		for d := m.path; d != filepath.Dir(d) && !found; d = filepath.Dir(d) {
+			found = filepath.Base(d) == name
+		}
+		for _, entry := range m.entries {
+			found = found || (entry.IsDir && entry.Name == name)
+		}
+		if found {
+			return "  |  S Versions"
+		}
+	}
+	return ""
+}
+
+func subdirs(dir string) []fs.DirEntry {
+	entries, _ := os.ReadDir(dir)
+	var dirs []fs.DirEntry
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, entry)
+		}
+	}
+	return dirs
+}
+
+// readAlias follows nvm's alias files, "default" -> "lts/iron" -> "v20".
+func readAlias(root, name string) string {
+	for i := 0; i < 4; i++ {
+		data, err := os.ReadFile(filepath.Join(root, "alias", name))
+		if err != nil {
+			return name
+		}
+		name = strings.TrimSpace(string(data))
+	}
+	return name
+}
+
+func readNvm(c *versionCollector, root string) {
+	group := c.group("Node", root)
+	alias := strings.TrimPrefix(readAlias(root, "default"), "v")
+	if alias != "" && unicode.IsDigit(rune(alias[0])) {
+		group.pinned = func(version string) string {
+			v := strings.TrimPrefix(version, "v")
+			if v == alias || strings.HasPrefix(v, alias+".") {
+				return "pinned (nvm default)"
+			}
+			return ""
+		}
+	}
+	dir := filepath.Join(root, "versions", "node")
+	for _, entry := range subdirs(dir) {
+		c.add(group, entry.Name(), filepath.Join(dir, entry.Name()))
+	}
+}
+
+func readPyenv(c *versionCollector, root string) {
+	group := c.group("Python", root)
+	if data, err := os.ReadFile(filepath.Join(root, "version")); err == nil {
+		global := strings.Fields(string(data))
+		group.pinned = func(version string) string {
+			for _, pin := range global {
+				if pin == version {
+					return "pinned (pyenv global)"
+				}
+			}
+			return ""
+		}
+	}
+	dir := filepath.Join(root, "versions")
+	for _, entry := range subdirs(dir) {
+		c.add(group, entry.Name(), filepath.Join(dir, entry.Name()))
+	}
+}
+
+// readRustup lists release toolchains together and dated nightlies and betas
+// on their own. Undated channels like stable are updated in place and kept.
+func readRustup(c *versionCollector, root string) {
+	var defaultToolchain string
+	if data, err := os.ReadFile(filepath.Join(root, "settings.toml")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "default_toolchain" {
+				defaultToolchain = strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		}
+	}
+	pinnedIn := func(channel string) func(string) string {
+		return func(version string) string {
+			toolchain := channel + version
+			if defaultToolchain != "" && (toolchain == defaultToolchain || strings.HasPrefix(toolchain, defaultToolchain+"-")) {
+				return "pinned (rustup default)"
+			}
+			return ""
+		}
+	}
+	dir := filepath.Join(root, "toolchains")
+	for _, entry := range subdirs(dir) {
+		name, channel, version := "Rust", "", entry.Name()
+		for _, dated := range []string{"nightly", "beta"} {
+			// nightly-2024-01-15-<target> is listed as 2024-01-15-<target> under Rust nightly
+			if rest, ok := strings.CutPrefix(entry.Name(), dated+"-"); ok && len(rest) > 0 && unicode.IsDigit(rune(rest[0])) {
+				name, channel, version = "Rust "+dated, dated+"-", rest
+			}
+		}
+		group := c.group(name, root)
+		group.pinned = pinnedIn(channel)
+		c.add(group, version, filepath.Join(dir, entry.Name()))
+	}
+}
+
+func readGradle(c *versionCollector, root string) {
+	dists := filepath.Join(root, "wrapper", "dists")
+	wrapper := c.group("Gradle", root)
+	for _, entry := range subdirs(dists) {
+		// gradle-8.5-bin and gradle-8.5-all are the same version
+		name := strings.TrimPrefix(entry.Name(), "gradle-")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, "-bin"), "-all")
+		c.add(wrapper, name, filepath.Join(dists, entry.Name()))
+	}
+	for _, area := range []struct{ dir, group string }{{"caches", "Gradle caches"}, {"daemon", "Gradle daemon logs"}} {
+		group := c.group(area.group, root)
+		for _, entry := range subdirs(filepath.Join(root, area.dir)) {
+			if unicode.IsDigit(rune(entry.Name()[0])) {
+				c.add(group, entry.Name(), filepath.Join(root, area.dir, entry.Name()))
+			}
+		}
+	}
+}
+
+// readMaven finds group/artifact/version directories by the
+// artifact-version.pom inside them.
+func readMaven(c *versionCollector, root string) {
+	repository := filepath.Join(root, "repository")
+	_ = filepath.WalkDir(repository, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || p == repository {
+			return nil
+		}
+		artifactDir := filepath.Dir(p)
+		artifact := filepath.Base(artifactDir)
+		if _, err := os.Stat(filepath.Join(p, artifact+"-"+d.Name()+".pom")); err != nil {
+			return nil
+		}
+		groupPath, _ := filepath.Rel(repository, filepath.Dir(artifactDir))
+		name := fmt.Sprintf("Maven %s:%s", strings.ReplaceAll(groupPath, string(filepath.Separator), "."), artifact)
+		c.add(c.group(name, root), d.Name(), p)
+		return filepath.SkipDir
+	})
+}
+
+// readCargoVersions groups the crates of the registry by name, pairing the
+// extracted sources with the downloaded .crate files.
+func readCargoVersions(c *versionCollector, root string) {
+	for _, crate := range listCargoCrates(filepath.Join(root, "registry")) {
+		group := c.group("Crate "+crate.Name, root)
+		for _, path := range crate.Paths {
+			c.add(group, crate.Version, path)
+		}
+	}
+}
+
+// readGoModVersions groups module@version directories by module, together
+// with their files in the download cache.
+func readGoModVersions(c *versionCollector, root string) {
+	for _, module := range listGoModules(filepath.Join(root, "pkg", "mod")) {
+		group := c.group("Go "+module.Path, root)
+		c.add(group, module.Version, module.Dir)
+		for _, path := range module.Downloads {
+			c.add(group, module.Version, path)
+		}
+	}
+}
+
+// versionNumbers extracts the leading dotted numbers of a version, nil when
+// it does not start with one ("stable", "lts").
+func versionNumbers(version string) []int {
+	numbers, _ := splitVersion(version)
+	return numbers
+}
+
+// splitVersion separates the numeric core of a version from what follows
+// it: 8.5-rc-1 is [8 5] and "-rc-1".
+func splitVersion(version string) (numbers []int, suffix string) {
+	version = strings.TrimPrefix(version, "v")
+	if version == "" || !unicode.IsDigit(rune(version[0])) {
+		return nil, version
+	}
+	end := strings.IndexFunc(version, func(r rune) bool { return r != '.' && !unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(version)
+	}
+	for _, field := range strings.Split(strings.TrimRight(version[:end], "."), ".") {
+		n, _ := strconv.Atoi(field)
+		numbers = append(numbers, n)
+	}
+	return numbers, version[end:]
+}
+
+// compareVersions orders by the numeric core; names without a version sort
+// first. With equal cores a suffixed version is a pre-release (8.5-rc-1,
+// v1.2.3-0.20230101-abcdef) and comes before the plain release, while two
+// suffixes compare naturally, as in nightly dates.
+func compareVersions(a, b string) int {
+	na, sa := splitVersion(a)
+	nb, sb := splitVersion(b)
+	switch {
+	case na == nil && nb == nil:
+		return strings.Compare(a, b)
+	case na == nil:
+		return 1
+	case nb == nil:
+		return -1
+	}
+	if c := compareNumbers(na, nb); c != 0 {
+		return c
+	}
+	switch {
+	case sa == sb:
+		return 0
+	case sa == "":
+		return 1
+	case sb == "":
+		return -1
+	}
+	return compareNatural(sa, sb)
+}
+
+// compareNumbers orders component by component, a longer list being newer
+// when one is a prefix of the other.
+func compareNumbers(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// compareNatural compares runs of digits by value and everything else as
+// text, so rc-10 follows rc-9 and alpha.1 precedes beta.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ta, tb := leadingRun(a), leadingRun(b)
+		a, b = a[len(ta):], b[len(tb):]
+		na, errA := strconv.Atoi(ta)
+		nb, errB := strconv.Atoi(tb)
+		c := strings.Compare(ta, tb)
+		if errA == nil && errB == nil {
+			c = compareNumbers([]int{na}, []int{nb})
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// leadingRun is the run of digits, or of other characters, s starts with.
+func leadingRun(s string) string {
+	digit := unicode.IsDigit(rune(s[0]))
+	end := strings.IndexFunc(s, func(r rune) bool { return unicode.IsDigit(r) != digit })
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
+
+func (m model) startVersions() (tea.Model, tea.Cmd) {
+	m.versionItems = nil
+	m.status = fmt.Sprintf("Reading toolchain caches under %s...", displayPath(m.path))
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	root, bytes, currentPath := m.path, m.bytesScanned, m.currentPath
+	load := func() tea.Msg {
+		unreadable := &insightCollector{}
+		items := findVersionedCaches(root, unreadable, bytes, currentPath)
+		return versionsMsg{root: root, items: items, unreadable: unreadable}
+	}
+	return m, tea.Batch(load, tickCmd())
+}
+
+func (m model) versionTotals() (old, marked int64, markedCount int) {
+	for _, item := range m.versionItems {
+		if item.Note == "" {
+			old += item.Size
+		}
+		if item.Marked {
+			marked += item.Size
+			markedCount++
+		}
+	}
+	return
+}
+
+func (m model) updateVersionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "S", "b", "left", "h":
+		m.showVersions = false
+	case "r":
+		return m.startVersions()
+	case "up", "k":
+		m.versionCursor.up()
+	case "down", "j":
+		m.versionCursor.down(len(m.versionItems), headedViewport(m.height))
+	case " ":
+		// Keep or drop the selected version
+		if m.versionCursor.Selected < len(m.versionItems) {
+			item := &m.versionItems[m.versionCursor.Selected]
+			item.Marked = !item.Marked
+		}
+	case "o", "f", "F":
+		if m.versionCursor.Selected < len(m.versionItems) {
+			item := m.versionItems[m.versionCursor.Selected]
+			openFn := openPathCommand
+			if msg.String() != "o" {
+				openFn = revealPathCommand
+			}
+			go func(path string) {
+				ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+				defer cancel()
+				_ = openFn(ctx, path).Run()
+			}(item.Paths[0].Path)
+		}
+	case "delete", "backspace":
+		var batch []dirEntry
+		for _, item := range m.versionItems {
+			if !item.Marked {
+				continue
+			}
+			for _, p := range item.Paths {
+				batch = append(batch, dirEntry{Name: filepath.Base(p.Path), Path: p.Path, Size: p.Size, IsDir: true})
+			}
+		}
+		if len(batch) > 0 {
+			m.deleteConfirm = true
+			m.deleteBatch = batch
+		}
+	}
+	return m, nil
+}
+
+// removeVersionPaths drops deleted paths and the versions left without any.
+func (m *model) removeVersionPaths(paths []string) {
+	if len(m.versionItems) == 0 || len(paths) == 0 {
+		return
+	}
+	removed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		removed[path] = true
+	}
+	kept := m.versionItems[:0]
+	for _, item := range m.versionItems {
+		remaining := item.Paths[:0]
+		item.Size = 0
+		for _, p := range item.Paths {
+			if !removed[p.Path] {
+				remaining = append(remaining, p)
+				item.Size += p.Size
+			}
+		}
+		item.Paths = remaining
+		if len(remaining) > 0 {
+			kept = append(kept, item)
+		}
+	}
+	m.versionItems = kept
+	m.versionCursor.clamp(len(m.versionItems), headedViewport(m.height))
+}
+
+func (m model) renderVersions(b *strings.Builder) {
+	if len(m.versionItems) == 0 {
+		fmt.Fprintln(b, "  No toolchain cache with several versions found (.nvm, .pyenv, .rustup, .gradle, .m2, .cargo, go/pkg/mod)")
+		return
+	}
+	old, marked, markedCount := m.versionTotals()
+	fmt.Fprintf(b, "%sOlder versions hold %s", colorGray, humanizeBytes(old))
+	if markedCount > 0 {
+		fmt.Fprintf(b, "  |  %sSelected: %d (%s)%s", colorYellow, markedCount, humanizeBytes(marked), colorGray)
+	}
+	fmt.Fprintf(b, "%s\n\n", colorReset)
+
+	maxSize := int64(1)
+	for _, item := range m.versionItems {
+		maxSize = max(maxSize, item.Size)
+	}
+	start, end := m.versionCursor.window(len(m.versionItems), headedViewport(m.height))
+	for idx := start; idx < end; idx++ {
+		item := m.versionItems[idx]
+		group := ""
+		if idx == start || m.versionItems[idx-1].Group != item.Group {
+			group = fmt.Sprintf("%s · %s", item.Group, filepath.Base(item.Root))
+		}
+		check := "☐"
+		if item.Marked {
+			check = colorYellow + "☑" + colorReset
+		}
+		entryPrefix := "   "
+		nameColor, sizeColor, numColor := "", colorGray, ""
+		if idx == m.versionCursor.Selected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			nameColor, sizeColor, numColor = colorCyan, colorCyan, colorCyan
+		}
+		note := item.Note
+		if note == "" {
+			note = "old"
+		}
+		noteColor := colorGray
+		if item.Note != "" {
+			noteColor = colorGreen
+		}
+		bar := coloredProgressBar(item.Size, maxSize, 0)
+		fmt.Fprintf(b, "%s%s%2d.%s %s %s  |  🧰 %s %s%s%s  %s%10s%s  %s%s%s\n",
+			entryPrefix, numColor, idx+1, colorReset, check, bar,
+			padName(truncateMiddle(group, 34), 34),
+			nameColor, padName(truncateMiddle(item.Version, 28), 28), colorReset,
+			sizeColor, humanizeBytes(item.Size), colorReset,
+			noteColor, note, colorReset)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int // Sign of compareVersions(a, b)
+	}{
+		{"v20.11.0", "v18.19.0", 1},
+		{"3.9.18", "3.12.1", -1},
+		{"1.75.0", "1.75.0", 0},
+		{"1.10", "1.9", 1},
+		{"8.5.1", "8.5", 1},
+		{"8.5", "8.5-rc-1", 1},
+		{"8.5-rc-2", "8.5-rc-1", 1},
+		{"8.5-rc-10", "8.5-rc-9", 1},
+		{"2.0.0", "2.0.0-RC1", 1},
+		{"2.0.0-RC1", "2.0.0-M3", 1},
+		{"1.0.0-beta", "1.0.0-alpha.1", 1},
+		{"3.13.0", "3.13.0a1", 1},
+		{"v1.2.3", "v1.2.3-0.20230101120000-abcdef123456", 1},
+		{"v1.2.4-0.20230101120000-abcdef123456", "v1.2.3", 1},
+		{"v1.2.3-0.20240101120000-abcdef123456", "v1.2.3-0.20230101120000-abcdef123456", 1},
+		{"2024-02-01-x86_64-unknown-linux-gnu", "2024-01-15-x86_64-unknown-linux-gnu", 1},
+		{"1.75.0-x86_64-unknown-linux-gnu", "1.74.0-x86_64-unknown-linux-gnu", 1},
+		{"stable", "1.75.0", 1},
+		{"lts", "stable", -1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+		if back := compareVersions(tt.b, tt.a); sign(back) != -tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign %d", tt.b, tt.a, back, -tt.want)
+		}
+	}
+}
+
+func TestVersionNumbers(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []int
+	}{
+		{"v20.11.0", []int{20, 11, 0}},
+		{"8.5-rc-1", []int{8, 5}},
+		{"2.0.0-RC1", []int{2, 0, 0}},
+		{"3.13.0a1", []int{3, 13, 0}},
+		{"1.2.Final", []int{1, 2}},
+		{"v1.2.3-0.20230101120000-abcdef123456", []int{1, 2, 3}},
+		{"stable", nil},
+		{"v", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := versionNumbers(tt.version); !slices.Equal(got, tt.want) {
+			t.Errorf("versionNumbers(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// runWarmCache scans the given roots headlessly and stores the results in the
+// on-disk cache, so the next interactive session opens with fresh numbers.
+// Intended for cron, launchd or systemd timers: it never touches the terminal,
+// lowers its own priority and refuses to run twice at the same time. depth
+// levels of subdirectories get cache entries of their own too.
+func runWarmCache(paths []string, depth int) error {
+	unlock, err := acquireWarmLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Background job: yield CPU to interactive work.
+	_ = syscall.Setpriority(syscall.PRIO_PROCESS, 0, warmNiceLevel)
+
+	roots := warmRoots(paths)
+	if len(roots) == 0 {
+		return fmt.Errorf("no directories to warm")
+	}
+
+	var failed int
+	for _, root := range roots {
+		start := time.Now()
+		total, dirs, err := warmPath(root, depth)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "warm %s: %v\n", displayPath(root), err)
+			continue
+		}
+		fmt.Printf("Warmed %s  %s  (%d dirs, %s)\n",
+			displayPath(root), humanizeBytes(total), dirs, time.Since(start).Round(time.Second))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d paths failed", failed, len(roots))
+	}
+	return nil
+}
+
+// warmRoots resolves explicit paths, falling back to the overview shortcuts.
+func warmRoots(paths []string) []string {
+	var roots []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		abs, err := filepath.Abs(path)
+		if err != nil || seen[abs] {
+			return
+		}
+		info, err := os.Stat(abs)
+		if err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "warm: skipping %s: not a directory\n", path)
+			return
+		}
+		seen[abs] = true
+		roots = append(roots, abs)
+	}
+
+	if len(paths) > 0 {
+		for _, path := range paths {
+			add(path)
+		}
+		return roots
+	}
+	for _, entry := range createOverviewEntries() {
+		add(entry.Path)
+	}
+	return roots
+}
+
+// warmPath rescans root, persists the result, then recurses into its
+// subdirectories until depth is exhausted. Each subdirectory is walked again
+// by its own scan, so at depth N the bottom level is read N+1 times; that is
+// why depth defaults to 0. Returns the root total and the number of
+// directories written to the cache.
+func warmPath(root string, depth int) (int64, int, error) {
+	var filesScanned, dirsScanned, bytesScanned int64
+	currentPath := ""
+
+	result, err := scanPathConcurrent(root, &filesScanned, &dirsScanned, &bytesScanned, &currentPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := saveCacheToDisk(root, result); err != nil {
+		return result.TotalSize, 0, err
+	}
+	if result.TotalSize > 0 {
+		_ = storeOverviewSize(root, result.TotalSize)
+	}
+
+	warmed := 1
+	if depth <= 0 {
+		return result.TotalSize, warmed, nil
+	}
+	for _, entry := range result.Entries {
+		if !entry.IsDir || shouldFoldDirWithPath(entry.Name, entry.Path) {
+			continue
+		}
+		// Child failures (permissions, races with deletion) must not abort the run.
+		if _, n, err := warmPath(entry.Path, depth-1); err == nil {
+			warmed += n
+		}
+	}
+	return result.TotalSize, warmed, nil
+}
+
+// acquireWarmLock takes an exclusive, non-blocking lock so overlapping timer
+// runs exit early instead of scanning the same trees in parallel.
+func acquireWarmLock() (func(), error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(cacheDir, warmLockFile)
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("another warm run is in progress")
+	}
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
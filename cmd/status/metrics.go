@@ -21,6 +21,7 @@ import (
 	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/net"
+	"github.com/tw93/mole/internal/disks"
 )
 
 type MetricsSnapshot struct {
@@ -164,16 +165,6 @@ const (
 	cpuSampleInterval     = 200 * time.Millisecond
 )
 
-var skipDiskMounts = map[string]bool{
-	"/System/Volumes/VM":       true,
-	"/System/Volumes/Preboot":  true,
-	"/System/Volumes/Update":   true,
-	"/System/Volumes/xarts":    true,
-	"/System/Volumes/Hardware": true,
-	"/System/Volumes/Data":     true,
-	"/dev":                     true,
-}
-
 func NewCollector() *Collector {
 	return &Collector{
 		prevNet: make(map[string]net.IOCountersStat),
@@ -577,112 +568,55 @@ func getMemoryPressure() string {
 }
 
 func collectDisks() ([]DiskStatus, error) {
-	partitions, err := disk.Partitions(false)
+	volumes, err := disks.List()
 	if err != nil {
 		return nil, err
 	}
 
-	var (
-		disks      []DiskStatus
-		seenDevice = make(map[string]bool)
-		seenVolume = make(map[string]bool)
-	)
-	for _, part := range partitions {
-		if strings.HasPrefix(part.Device, "/dev/loop") {
-			continue
-		}
-		if skipDiskMounts[part.Mountpoint] {
-			continue
-		}
-		if strings.HasPrefix(part.Mountpoint, "/System/Volumes/") {
-			continue
-		}
-		// Skip private volumes
-		if strings.HasPrefix(part.Mountpoint, "/private/") {
-			continue
-		}
-		baseDevice := baseDeviceName(part.Device)
-		if baseDevice == "" {
-			baseDevice = part.Device
-		}
-		if seenDevice[baseDevice] {
-			continue
-		}
-		usage, err := disk.Usage(part.Mountpoint)
-		if err != nil || usage.Total == 0 {
-			continue
-		}
-		// Skip small volumes (< 1GB)
-		if usage.Total < 1<<30 {
-			continue
-		}
-		// For APFS volumes, use a more precise dedup key (bytes level)
-		// to handle shared storage pools properly
-		volKey := fmt.Sprintf("%s:%d", part.Fstype, usage.Total)
-		if seenVolume[volKey] {
-			continue
-		}
-		disks = append(disks, DiskStatus{
-			Mount:       part.Mountpoint,
-			Device:      part.Device,
-			Used:        usage.Used,
-			Total:       usage.Total,
-			UsedPercent: usage.UsedPercent,
-			Fstype:      part.Fstype,
+	var result []DiskStatus
+	for _, volume := range volumes {
+		result = append(result, DiskStatus{
+			Mount:       volume.Mount,
+			Device:      volume.Device,
+			Used:        volume.Used,
+			Total:       volume.Total,
+			UsedPercent: volume.UsedPercent,
+			Fstype:      volume.Fstype,
 		})
-		seenDevice[baseDevice] = true
-		seenVolume[volKey] = true
 	}
 
-	annotateDiskTypes(disks)
-
-	sort.Slice(disks, func(i, j int) bool {
-		return disks[i].Total > disks[j].Total
-	})
+	annotateDiskTypes(result)
 
-	if len(disks) > 3 {
-		disks = disks[:3]
+	if len(result) > 3 {
+		result = result[:3]
 	}
 
-	return disks, nil
+	return result, nil
 }
 
-func annotateDiskTypes(disks []DiskStatus) {
-	if len(disks) == 0 || runtime.GOOS != "darwin" || !commandExists("diskutil") {
+func annotateDiskTypes(list []DiskStatus) {
+	if len(list) == 0 || runtime.GOOS != "darwin" || !commandExists("diskutil") {
 		return
 	}
 	cache := make(map[string]bool)
-	for i := range disks {
-		base := baseDeviceName(disks[i].Device)
+	for i := range list {
+		base := disks.BaseDeviceName(list[i].Device)
 		if base == "" {
-			base = disks[i].Device
+			base = list[i].Device
 		}
 		if val, ok := cache[base]; ok {
-			disks[i].External = val
+			list[i].External = val
 			continue
 		}
 		external, err := isExternalDisk(base)
 		if err != nil {
-			external = strings.HasPrefix(disks[i].Mount, "/Volumes/")
+			external = strings.HasPrefix(list[i].Mount, "/Volumes/")
 		}
-		disks[i].External = external
+		list[i].External = external
 		cache[base] = external
 	}
 }
 
-func baseDeviceName(device string) string {
-	device = strings.TrimPrefix(device, "/dev/")
-	if !strings.HasPrefix(device, "disk") {
-		return device
-	}
-	for i := 4; i < len(device); i++ {
-		if device[i] == 's' {
-			return device[:i]
-		}
-	}
-	return device
-}
-
 func isExternalDisk(device string) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
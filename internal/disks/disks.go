@@ -0,0 +1,114 @@
+// Package disks enumerates the mounted filesystems worth showing to a user,
+// shared by mo status and mo analyze so both list the same volumes.
+package disks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// Volume is one mounted filesystem with its usage.
+type Volume struct {
+	Mount       string
+	Device      string
+	Fstype      string
+	Total       uint64
+	Used        uint64
+	Free        uint64
+	UsedPercent float64
+}
+
+var skipMounts = map[string]bool{
+	"/System/Volumes/VM":       true,
+	"/System/Volumes/Preboot":  true,
+	"/System/Volumes/Update":   true,
+	"/System/Volumes/xarts":    true,
+	"/System/Volumes/Hardware": true,
+	"/System/Volumes/Data":     true,
+	"/dev":                     true,
+}
+
+// List returns real mounted filesystems, largest first. Loop devices, system
+// and private volumes, volumes under 1GB and duplicate views of the same
+// device or APFS pool are left out.
+func List() ([]Volume, error) {
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		volumes    []Volume
+		seenDevice = make(map[string]bool)
+		seenVolume = make(map[string]bool)
+	)
+	for _, part := range partitions {
+		if strings.HasPrefix(part.Device, "/dev/loop") {
+			continue
+		}
+		if skipMounts[part.Mountpoint] {
+			continue
+		}
+		if strings.HasPrefix(part.Mountpoint, "/System/Volumes/") {
+			continue
+		}
+		// Skip private volumes
+		if strings.HasPrefix(part.Mountpoint, "/private/") {
+			continue
+		}
+		baseDevice := BaseDeviceName(part.Device)
+		if baseDevice == "" {
+			baseDevice = part.Device
+		}
+		if seenDevice[baseDevice] {
+			continue
+		}
+		usage, err := disk.Usage(part.Mountpoint)
+		if err != nil || usage.Total == 0 {
+			continue
+		}
+		// Skip small volumes (< 1GB)
+		if usage.Total < 1<<30 {
+			continue
+		}
+		// For APFS volumes, use a more precise dedup key (bytes level)
+		// to handle shared storage pools properly
+		volKey := fmt.Sprintf("%s:%d", part.Fstype, usage.Total)
+		if seenVolume[volKey] {
+			continue
+		}
+		volumes = append(volumes, Volume{
+			Mount:       part.Mountpoint,
+			Device:      part.Device,
+			Fstype:      part.Fstype,
+			Total:       usage.Total,
+			Used:        usage.Used,
+			Free:        usage.Free,
+			UsedPercent: usage.UsedPercent,
+		})
+		seenDevice[baseDevice] = true
+		seenVolume[volKey] = true
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Total > volumes[j].Total
+	})
+	return volumes, nil
+}
+
+// BaseDeviceName strips the partition suffix of a macOS device, disk3s1 -> disk3.
+func BaseDeviceName(device string) string {
+	device = strings.TrimPrefix(device, "/dev/")
+	if !strings.HasPrefix(device, "disk") {
+		return device
+	}
+	for i := 4; i < len(device); i++ {
+		if device[i] == 's' {
+			return device[:i]
+		}
+	}
+	return device
+}